@@ -2,96 +2,95 @@ package cmd
 
 import (
 	"bufio"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"strings"
 	"syscall"
-	"time"
 
+	"github.com/UK2Group/ics-cli/icsapi"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/term"
 )
 
-// UserResponse represents the structure of the API response
-type UserResponse struct {
-	Data struct {
-		UserProfile struct {
-			Username string `json:"username"`
-		} `json:"userProfile"`
-	} `json:"data"`
-}
-
 // loginCmd represents the login command
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to your Ingenuity Cloud Services Account (requires an API Key)",
-	Run: func(cmd *cobra.Command, args []string) {
-		apiKey, err := promptForAPIKey(cmd)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error reading API key:", err)
-			return
+	Long: `Login to your Ingenuity Cloud Services Account with an API key.
+
+The key is taken from --key, ICS_API_KEY or a prompt, in that order, and saved to
+the current profile, or to the profile given with --profile,
+which is created if it does not exist. An --api-url given at login is saved with it.
+
+The key is encrypted with a passphrase in a credential file next to the config file,
+and the config file only keeps a reference to it. Once unlocked, the credential file
+stays unlocked for credential_cache_ttl (default 15m), cached in $XDG_RUNTIME_DIR or in
+the user cache directory. Set ICS_PASSPHRASE to unlock it without a prompt, or credential_store to
+"plaintext" to keep the key in the config file, which is required without a terminal
+unless ICS_PASSPHRASE is set.
+API keys left in plaintext by earlier versions are moved to the credential file.
+
+With a credential_helper configured and neither --key nor ICS_API_KEY, the key returned by the helper is
+verified and nothing is saved.`,
+	Example: `  # Login to the current profile
+  ics-cli auth login
+
+  # Add a profile for another account
+  ics-cli auth login --profile acme`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateProfileName(activeProfile); err != nil {
+			return err
 		}
 
-		// Verify API key by making a test API call
-		client := &http.Client{Timeout: 10 * time.Second}
-		req, err := http.NewRequest("GET", "https://api.ingenuitycloudservices.com/rest-api/user/details", nil)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error creating request:", err)
-			return
+		// A credential helper provides the key, so there is nothing to save
+		if givenAPIKey(cmd) == "" && newCredentialHelper() != nil {
+			return loginWithCredentialHelper(cmd)
 		}
 
-		req.Header.Add("X-Api-Token", apiKey)
-
-		fmt.Println("Verifying API key...")
-		resp, err := client.Do(req)
+		apiKey, err := promptForAPIKey(cmd)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error connecting to API:", err)
-			return
+			return fmt.Errorf("failed to read API key: %w", err)
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode == http.StatusUnauthorized {
-			fmt.Fprintln(os.Stderr, "Error: Invalid API key. Authentication failed.")
-			return
-		}
+		// Verify API key by making a test API call
+		client := newAPIClientWithKey(apiKey)
 
-		if resp.StatusCode != http.StatusOK {
-			fmt.Fprintf(os.Stderr, "Error: API returned status code %d\n", resp.StatusCode)
-			return
+		showActiveProfile()
+		fmt.Println("Verifying API key...")
+		profile, err := client.UserDetails(cmd.Context())
+		if errors.Is(err, icsapi.ErrUnauthorized) {
+			return authError("invalid API key. Authentication failed")
 		}
-
-		// Read and parse the response
-		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error reading API response:", err)
-			return
-		}
-
-		var userResp UserResponse
-		if err := json.Unmarshal(body, &userResp); err != nil {
-			fmt.Fprintln(os.Stderr, "Error parsing API response:", err)
-			return
+			return err
 		}
 
-		username := userResp.Data.UserProfile.Username
+		username := profile.Username
 		if username == "" {
 			fmt.Fprintln(os.Stderr, "Warning: Could not get username from API response")
 		}
 
-		// Store API key in Viper configuration
-		viper.Set("api_key", apiKey)
+		// Store the API key in the active profile, along with the API URL it was verified against
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		if err := saveAPIKey(config, activeProfile, apiKey); err != nil {
+			return fmt.Errorf("failed to save API key: %w", err)
+		}
+		if cmd.Flags().Changed("api-url") {
+			config.setProfileValue(activeProfile, "api_url", viper.GetString("api_url"))
+		}
 
-		// Save the config file
-		if err := viper.WriteConfig(); err != nil {
-			// If the config file doesn't exist, create it
-			if err := viper.SafeWriteConfig(); err != nil {
-				fmt.Fprintln(os.Stderr, "Error saving API key to config:", err)
-				return
-			}
+		// Move the keys earlier versions saved in plaintext to the credential store
+		migrated, err := migratePlaintextKeys(config)
+		if err != nil {
+			return fmt.Errorf("failed to move plaintext API keys to the credential store: %w", err)
+		}
+		if err := config.save(); err != nil {
+			return fmt.Errorf("failed to save API key to config: %w", err)
 		}
 
 		if username != "" {
@@ -99,13 +98,52 @@ var loginCmd = &cobra.Command{
 		} else {
 			fmt.Println("Successfully logged in to Ingenuity Cloud Services!")
 		}
+		if len(migrated) > 0 {
+			fmt.Printf("Moved the plaintext API keys of profiles %s to the %s credential store\n", strings.Join(migrated, ", "), selectedCredentialStore())
+		}
+		if activeProfile != config.currentProfile() {
+			fmt.Printf("Saved to profile %s. Run 'ics-cli profile use %s' to make it the current profile\n", activeProfile, activeProfile)
+		}
+		return nil
 	},
 }
 
+// loginWithCredentialHelper verifies the key returned by the credential helper
+func loginWithCredentialHelper(cmd *cobra.Command) error {
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Verifying API key from the credential helper...")
+	profile, err := client.UserDetails(cmd.Context())
+	if errors.Is(err, icsapi.ErrUnauthorized) {
+		return authError("the credential helper returned an invalid API key. Authentication failed")
+	}
+	if err != nil {
+		return err
+	}
+
+	if profile.Username != "" {
+		fmt.Printf("Successfully logged in as %s with the credential helper\n", profile.Username)
+	} else {
+		fmt.Println("Successfully logged in to Ingenuity Cloud Services with the credential helper")
+	}
+	return nil
+}
+
+// givenAPIKey returns the API key given with --key, or else with ICS_API_KEY
+func givenAPIKey(cmd *cobra.Command) string {
+	if apiKey, _ := cmd.Flags().GetString("key"); apiKey != "" {
+		return apiKey
+	}
+	return os.Getenv(envVarName("api_key"))
+}
+
 // promptForAPIKey asks the user to input their API key
 func promptForAPIKey(cmd *cobra.Command) (string, error) {
-	// Check if API key was provided via flag
-	if apiKey, _ := cmd.Flags().GetString("key"); apiKey != "" {
+	// Check if API key was provided via flag or environment variable
+	if apiKey := givenAPIKey(cmd); apiKey != "" {
 		return apiKey, nil
 	}
 
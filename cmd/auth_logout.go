@@ -4,28 +4,45 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // logoutCmd represents the logout command
 var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Logout of the Ingenuity Cloud Services API",
-	Run: func(cmd *cobra.Command, args []string) {
-		// Check if there is an API key to delete
-		if viper.IsSet("api_key") {
-			// Remove API key from configuration
-			viper.Set("api_key", "")
+	Long: `Remove the API key of the current profile, or of the profile given with --profile,
+from the config file and the credential store. The other settings of the profile are kept,
+and the credential file is locked again.
+API keys of other profiles left in plaintext by earlier versions are moved to the
+credential store.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		// Remove the API key from the profile and its credential store
+		removed := deleteAPIKey(config, activeProfile)
+
+		// Lock the credential file again once done with it
+		defer lockCredentialFile()
+
+		if removed {
+			// Move the keys earlier versions saved in plaintext to the credential store
+			if _, err := migratePlaintextKeys(config); err != nil {
+				return fmt.Errorf("failed to move plaintext API keys to the credential store: %w", err)
+			}
 
 			// Save the updated configuration
-			if err := viper.WriteConfig(); err != nil {
-				fmt.Println("Error removing API key from configuration:", err)
-				return
+			if err := config.save(); err != nil {
+				return fmt.Errorf("failed to remove API key from configuration: %w", err)
 			}
+			showActiveProfile()
 			fmt.Println("Successfully logged out from Ingenuity Cloud Services API")
 		} else {
 			fmt.Println("You are not currently logged in")
 		}
+		return nil
 	},
 }
 
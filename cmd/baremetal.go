@@ -8,6 +8,16 @@ import (
 var baremetalCmd = &cobra.Command{
 	Use:   "baremetal",
 	Short: "Create, Destroy and Manage Baremetal Servers",
+	Long: `Create, Destroy and Manage Baremetal Servers.
+
+Commands acting on a server accept any of the following to identify it:
+  - a service ID or server ID, e.g. 123456
+  - a hostname or friendly name, e.g. web-01.example.com or web-01
+  - a public or secondary IP address, e.g. 192.0.2.10
+  - a glob matched against hostnames and friendly names, e.g. 'web-*'
+
+Names are matched case-insensitively. If several servers match, the candidates
+are listed and the command exits with a usage error.`,
 }
 
 func init() {
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+)
+
+// defaultParallel is the default number of servers acted on at the same time
+const defaultParallel = 5
+
+// powerAction describes a power command that can act on many servers
+type powerAction struct {
+	name    string // Name of the action in results, e.g. "poweroff"
+	command string // Command named in error messages, e.g. "power"
+	prompt  string // Confirmation prompt, with %s replaced by the servers
+	success string // Message for a server the action succeeded on
+	failure string // Message for a server the API reported the action failed on
+	run     func(client *icsapi.Client, ctx context.Context, serverID string) (bool, error)
+	waitFor bool // Power state the server reaches with --wait
+	cycle   bool // Whether the server powers off and on again, as for a reboot
+}
+
+// bulkResult is the outcome of an action on one server
+type bulkResult struct {
+	server  icsapi.Server
+	success bool  // Whether the API reported success
+	err     error // Error sending the request, if any
+}
+
+// addServerSelectionFlags adds the flags selecting the servers a bulk command acts on
+func addServerSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("site", "s", "", "Act on the servers in a site, e.g. NYC1")
+	cmd.Flags().Bool("all", false, "Act on every server in the account")
+	cmd.Flags().StringArray("match", nil, "Act on the servers whose hostname or friendly name matches a glob, e.g. 'web-*' (repeatable)")
+	cmd.Flags().Int("parallel", defaultParallel, "Number of servers to act on at the same time")
+}
+
+// isBulkSelection reports whether a command line may select several servers.
+// Bulk commands print a list of results rather than a single one in that case.
+func isBulkSelection(cmd *cobra.Command, args []string) bool {
+	site, _ := cmd.Flags().GetString("site")
+	all, _ := cmd.Flags().GetBool("all")
+	patterns, _ := cmd.Flags().GetStringArray("match")
+
+	return len(args) != 1 || icsapi.IsServerPattern(args[0]) || site != "" || all || len(patterns) > 0
+}
+
+// selectServers returns the servers selected by the arguments and the --site, --all
+// and --match flags of a command. Servers selected more than once are returned once.
+func selectServers(cmd *cobra.Command, client *icsapi.Client, args []string) ([]icsapi.Server, error) {
+	site, _ := cmd.Flags().GetString("site")
+	all, _ := cmd.Flags().GetBool("all")
+	patterns, _ := cmd.Flags().GetStringArray("match")
+
+	if all && (len(args) > 0 || len(patterns) > 0) {
+		return nil, usageError("--all cannot be combined with servers or --match")
+	}
+	if !all && site == "" && len(args) == 0 && len(patterns) == 0 {
+		return nil, usageError("no servers specified. Pass one or more servers, --match, --site or --all")
+	}
+
+	servers, err := client.ListServers(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	// --all, or --site on its own, starts from every server
+	var selected []icsapi.Server
+	if all || (len(args) == 0 && len(patterns) == 0) {
+		selected = servers
+	}
+
+	for _, query := range args {
+		matches, err := client.ResolveServersIn(cmd.Context(), servers, query)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only globs may select several servers
+		if len(matches) > 1 && !icsapi.IsServerPattern(query) {
+			return nil, &icsapi.AmbiguousServerError{Query: query, Candidates: matches}
+		}
+		selected = append(selected, matches...)
+	}
+
+	for _, pattern := range patterns {
+		matches, err := client.ResolveServersIn(cmd.Context(), servers, pattern)
+		if err != nil && !errors.Is(err, icsapi.ErrNotFound) {
+			return nil, err
+		}
+		selected = append(selected, matches...)
+	}
+
+	// Filter by site and remove duplicates
+	var result []icsapi.Server
+	seen := make(map[string]bool)
+	for _, server := range selected {
+		if site != "" && !strings.EqualFold(server.DatacenterName, site) {
+			continue
+		}
+		if seen[server.ID] {
+			continue
+		}
+		seen[server.ID] = true
+		result = append(result, server)
+	}
+
+	if len(result) == 0 {
+		return nil, &exitError{code: exitNotFound, err: fmt.Errorf("no servers match the selection")}
+	}
+
+	return result, nil
+}
+
+// confirmServers asks the user to confirm an action on the listed servers
+func confirmServers(servers []icsapi.Server, prompt string) bool {
+	if len(servers) == 1 {
+		target := fmt.Sprintf("%s (%d)", servers[0].Hostname, servers[0].ServiceID)
+		fmt.Fprintf(os.Stderr, "Are you sure you want to %s? (y/n): ", fmt.Sprintf(prompt, target))
+	} else {
+		target := fmt.Sprintf("the following %d servers", len(servers))
+		fmt.Fprintf(os.Stderr, "%s\n", BlueHeading(fmt.Sprintf("Are you sure you want to %s?", fmt.Sprintf(prompt, target))))
+		for _, server := range servers {
+			fmt.Fprintf(os.Stderr, "  %d  %s  %s\n", server.ServiceID, server.Hostname, server.DatacenterName)
+		}
+		fmt.Fprint(os.Stderr, "Proceed? (y/n): ")
+	}
+
+	var response string
+	fmt.Scanln(&response)
+	return response == "y"
+}
+
+// runBulk calls fn for every server, with at most parallel calls at the same time.
+// Results are returned in the order of servers.
+func runBulk(ctx context.Context, servers []icsapi.Server, parallel int, fn func(ctx context.Context, server icsapi.Server) (bool, error)) []bulkResult {
+	results := make([]bulkResult, len(servers))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(parallel, len(servers)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				success, err := fn(ctx, servers[i])
+				results[i] = bulkResult{server: servers[i], success: success, err: err}
+			}
+		}()
+	}
+
+	for i := range servers {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// runPowerAction selects servers, confirms and runs a power action on them, then prints the results
+func runPowerAction(cmd *cobra.Command, args []string, action powerAction) error {
+	if err := applyConfigDefault(cmd, "parallel", "parallel"); err != nil {
+		return err
+	}
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	if parallel < 1 {
+		return usageError("--parallel must be at least 1")
+	}
+
+	// Every flag is checked before the user is asked to confirm
+	wait, timeout, interval, err := getWaitFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	servers, err := selectServers(cmd, client, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if user wants to proceed
+	dontPrompt, _ := cmd.Flags().GetBool("dont")
+	if !dontPrompt && !confirmServers(servers, action.prompt) {
+		return abortedError("aborted by user")
+	}
+
+	var status *progress
+	var ready atomic.Int32
+	if wait {
+		status = newProgress(fmt.Sprintf("Sending %s command to %d server(s)...", action.command, len(servers)))
+		defer status.Stop()
+	}
+
+	results := runBulk(cmd.Context(), servers, parallel, func(ctx context.Context, server icsapi.Server) (bool, error) {
+		success, err := action.run(client, ctx, server.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to send %s command: %w", action.command, err)
+		}
+		if !success || !wait {
+			return success, nil
+		}
+
+		// Poll the power status until the server reaches the expected state
+		if len(servers) == 1 {
+			status.Update(fmt.Sprintf("Waiting for %s to power %s...", server.Hostname, onOff(action.waitFor)))
+		}
+		err = waitForPower(ctx, client, server.ID, action, timeout, interval)
+		if err != nil {
+			return false, fmt.Errorf("%s (%d): %w", server.Hostname, server.ServiceID, err)
+		}
+
+		if len(servers) > 1 {
+			status.Update(fmt.Sprintf("Waiting for power state: %d of %d servers ready...", ready.Add(1), len(servers)))
+		}
+		return true, nil
+	})
+
+	if wait {
+		status.Stop()
+	}
+
+	if !isBulkSelection(cmd, args) {
+		result := results[0]
+		if result.err != nil {
+			return result.err
+		}
+
+		message := action.success
+		if !result.success {
+			message = action.failure
+		}
+		return printActionResult(newActionResult(result.server.ServiceID, action.name, result.success, message))
+	}
+
+	return printBulkResults(action, results)
+}
+
+// printBulkResults prints a per-server summary of a bulk action. It returns an error with
+// the exitPartial code if the action failed on some servers, or the code of the first
+// failure if it failed on every server.
+func printBulkResults(action powerAction, results []bulkResult) error {
+	var actionResults []actionResult
+	var firstErr error
+	failed := 0
+
+	for _, result := range results {
+		res := newActionResult(result.server.ServiceID, action.name, true, action.success)
+		switch {
+		case result.err != nil:
+			res.Success, res.Message = false, result.err.Error()
+		case !result.success:
+			res.Success, res.Message = false, action.failure
+		}
+		if !res.Success {
+			failed++
+			if firstErr == nil {
+				firstErr = result.err
+				if firstErr == nil {
+					firstErr = rejectedError("%s", action.failure)
+				}
+			}
+		}
+		actionResults = append(actionResults, res)
+	}
+
+	if !isTableOutput() {
+		if err := printStructured(actionResults); err != nil {
+			return err
+		}
+	} else {
+		headerFmt := color.New(color.FgBlue).SprintfFunc()
+		columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+		tbl := table.New("Service ID", "Hostname", "Result", "Message")
+		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+		for i, res := range actionResults {
+			status := GreenText("OK")
+			if !res.Success {
+				status = RedText("FAILED")
+			}
+			tbl.AddRow(res.ServiceID, results[i].server.Hostname, status, res.Message)
+		}
+
+		tbl.Print()
+
+		summary := fmt.Sprintf("\n%d of %d servers succeeded", len(results)-failed, len(results))
+		if failed == 0 {
+			fmt.Println(BlueHeading(summary))
+		} else {
+			fmt.Println(RedText(summary))
+		}
+	}
+
+	switch {
+	case failed == 0:
+		return nil
+	case failed == len(results):
+		return &exitError{code: exitCode(firstErr), err: firstErr, silent: true}
+	default:
+		return &exitError{code: exitPartial, err: fmt.Errorf("%d of %d servers failed", failed, len(results)), silent: true}
+	}
+}
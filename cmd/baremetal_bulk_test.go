@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+)
+
+func TestRunBulk(t *testing.T) {
+	tests := []struct {
+		name     string
+		servers  int
+		parallel int
+	}{
+		{name: "one server", servers: 1, parallel: 5},
+		{name: "fewer servers than workers", servers: 3, parallel: 5},
+		{name: "more servers than workers", servers: 20, parallel: 4},
+		{name: "one at a time", servers: 5, parallel: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var servers []icsapi.Server
+			for i := 0; i < tt.servers; i++ {
+				servers = append(servers, icsapi.Server{ID: strconv.Itoa(i), ServiceID: i})
+			}
+
+			var running, peak atomic.Int32
+			results := runBulk(context.Background(), servers, tt.parallel, func(ctx context.Context, server icsapi.Server) (bool, error) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+
+				// Odd servers fail, so results can be told apart
+				if server.ServiceID%2 == 1 {
+					return false, errors.New("failed")
+				}
+				return true, nil
+			})
+
+			if got := int(peak.Load()); got > tt.parallel {
+				t.Errorf("%d calls ran at the same time, want at most %d", got, tt.parallel)
+			}
+			if len(results) != len(servers) {
+				t.Fatalf("%d results, want %d", len(results), len(servers))
+			}
+			for i, result := range results {
+				if result.server.ServiceID != i {
+					t.Errorf("result %d is for server %d, want results in the order of servers", i, result.server.ServiceID)
+				}
+				if wantSuccess := i%2 == 0; result.success != wantSuccess || (result.err == nil) != wantSuccess {
+					t.Errorf("result %d: success %t, err %v", i, result.success, result.err)
+				}
+			}
+		})
+	}
+}
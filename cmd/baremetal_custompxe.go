@@ -2,15 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"strconv"
 
 	"github.com/spf13/cobra"
 )
 
 // custompxeCmd represents the custompxe command
 var custompxeCmd = &cobra.Command{
-	Use:   "custompxe [serviceID]",
+	Use:   "custompxe [server]",
 	Short: "Specify a custom PXE boot URL to load your preferred network boot environment",
 	Long: `Specify a custom PXE boot URL to load your preferred network boot environment.
 This allows you to deploy custom operating systems, recovery tools, or provisioning scripts tailored to your needs.
@@ -19,42 +17,31 @@ Your server will be rebooted to apply the custom PXE boot.
 Incorrect configurations may result in boot failures. Ensure your PXE setup is properly configured before proceeding.`,
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"pxe"},
-	Run: func(cmd *cobra.Command, args []string) {
-
-		// Get service ID from arguments
-		serviceID := args[0]
-
-		// Validate service ID is a number
-		if _, err := strconv.Atoi(serviceID); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be a number")
-			return
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
 
 		// Check if URL is provided
 		url, _ := cmd.Flags().GetString("url")
 		if url == "" {
-			fmt.Fprintln(os.Stderr, "Error: URL is required with --url or -u flag")
-			return
+			return usageError("URL is required with --url or -u flag")
 		}
 
-		// Step 1: Get the server ID from service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
+		client, err := newAPIClient()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
-		// Set the custom PXE URL
-		customPXE, err := setPXEUrl(serverID, url)
+		// Resolve the server argument
+		server, err := client.ResolveServer(cmd.Context(), args[0])
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error setting PXE URL:", err)
-			return
+			return err
 		}
 
-		if customPXE {
-			fmt.Printf("%s\n", BlueHeading("Successfully updated the server with custom PXE URL and requested a reboot"))
+		// Set the custom PXE URL
+		if err := client.SetPXEURL(cmd.Context(), server.ID, url); err != nil {
+			return fmt.Errorf("failed to set PXE URL: %w", err)
 		}
 
+		return printActionResult(newActionResult(server.ServiceID, "custompxe", true, "Successfully updated the server with custom PXE URL and requested a reboot"))
 	},
 }
 
@@ -1,11 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 )
 
@@ -18,123 +13,46 @@ var bmdAddonsCmd = &cobra.Command{
 - Software Licenses
 - Support Levels
 
-You must specify both the SKU (server type) and datacenter location.`,
+You must specify both the SKU (server type) and datacenter location. The datacenter
+defaults to the site config key.`,
 	Example: `  # List all add-ons for a specific server type in a location
   ics-cli baremetal list-addons --sku c1.small --datacenter NYC1`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The datacenter defaults to the site config key
+		if err := applyConfigDefault(cmd, "datacenter", "site"); err != nil {
+			return err
+		}
+
 		// Get required parameters
 		sku, _ := cmd.Flags().GetString("sku")
 		datacenter, _ := cmd.Flags().GetString("datacenter")
 
 		// Validate required parameters
 		if sku == "" {
-			fmt.Fprintln(os.Stderr, "Error: --sku flag is required")
-			return
+			return usageError("--sku flag is required")
 		}
 
 		if datacenter == "" {
-			fmt.Fprintln(os.Stderr, "Error: --datacenter flag is required")
-			return
+			return usageError("--datacenter flag is required, or set a default with 'ics-cli config set site NYC1'")
 		}
 
-		// Get add-ons for the specified SKU and datacenter
-		addons, err := getAddons(sku, datacenter)
+		client, err := newAPIClient()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting add-ons: %v\n", err)
-			return
+			return err
 		}
 
-		// Display the add-ons in tables
-		headerFmt := color.New(color.FgBlue, color.Bold).SprintfFunc()
-		columnFmt := color.New(color.FgYellow).SprintfFunc()
-		headerText := color.New(color.FgGreen, color.Bold).SprintfFunc()
-
-		// Display Operating Systems
-		if len(addons.Data.OperatingSystems.Products) > 0 {
-			fmt.Println(headerText("\n%s", addons.Data.OperatingSystems.Name))
-
-			// Create table for Operating Systems
-			osTbl := table.New("Name", "Type", "Product Code", "Price")
-			osTbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
-
-			// Add each OS to the table
-			for _, os := range addons.Data.OperatingSystems.Products {
-				// Format price
-				priceStr := "Free"
-				if os.Price > 0 {
-					priceStr = "$" + fmt.Sprintf("%.2f", os.Price) + " /mo"
-				}
-
-				// Check if price is per core
-				if os.PricePerCore != nil {
-					if perCore, ok := os.PricePerCore.(float64); ok && perCore > 0 {
-						priceStr = fmt.Sprintf("$%.2f per core", perCore) + " /mo"
-					}
-				}
-
-				osTbl.AddRow(
-					os.Name,
-					os.OSType,
-					os.ProductCode,
-					priceStr,
-				)
-			}
-
-			osTbl.Print()
+		// Get add-ons for the specified SKU and datacenter
+		addons, err := client.Addons(cmd.Context(), sku, datacenter)
+		if err != nil {
+			return err
 		}
 
-		// Display Licenses
-		if len(addons.Data.Licenses.Products) > 0 {
-			fmt.Println(headerText("\n%s", addons.Data.Licenses.Name))
-
-			// Create table for Licenses
-			licTbl := table.New("Name", "Product Code", "Price")
-			licTbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
-
-			// Add each license to the table
-			for _, lic := range addons.Data.Licenses.Products {
-				// Format price
-				priceStr := "Free"
-				if lic.Price > 0 {
-					priceStr = "$" + fmt.Sprintf("%.2f", lic.Price) + " /mo"
-				}
-
-				licTbl.AddRow(
-					lic.Name,
-					lic.ProductCode,
-					priceStr,
-				)
-			}
-
-			licTbl.Print()
+		if !isTableOutput() {
+			return printStructured(addons)
 		}
 
-		// Display Support Levels
-		if len(addons.Data.SupportLevels.Products) > 0 {
-			fmt.Println(headerText("\n%s", addons.Data.SupportLevels.Name))
-
-			// Create table for Support Levels
-			supTbl := table.New("Name", "Description", "Product Code", "Price")
-			supTbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
-
-			// Add each support level to the table
-			for _, sup := range addons.Data.SupportLevels.Products {
-				// Format price
-				priceStr := "Free"
-				if sup.Price > 0 {
-					priceStr = "$" + fmt.Sprintf("%.2f", sup.Price) + " /mo"
-				}
-
-				supTbl.AddRow(
-					sup.Name,
-					sup.Description,
-					sup.ProductCode,
-					priceStr,
-				)
-			}
-
-			supTbl.Print()
-		}
+		printAddons(addons)
+		return nil
 	},
 }
 
@@ -143,9 +61,8 @@ func init() {
 
 	// Add required flags
 	bmdAddonsCmd.Flags().String("sku", "", "Server type/SKU (required, e.g., c1.small)")
-	bmdAddonsCmd.Flags().String("datacenter", "", "Datacenter location (required, e.g., NYC1)")
+	bmdAddonsCmd.Flags().String("datacenter", "", "Datacenter location (required unless the site config key is set, e.g., NYC1)")
 
 	// Mark flags as required
 	bmdAddonsCmd.MarkFlagRequired("sku")
-	bmdAddonsCmd.MarkFlagRequired("datacenter")
 }
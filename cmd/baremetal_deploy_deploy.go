@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 )
 
@@ -24,66 +30,45 @@ Optional parameters include:
 --license Software licenses
 --bandwidth Additional bandwidth
 --support Support level
---quantity Quantity (defaults to 1)`,
+--quantity Quantity (defaults to 1)
+--wait Wait for the servers to be provisioned
+--dry-run Check the order without placing it
+
+Before the order is placed, the SKU, stock, add-on product codes and SSH keys are
+checked against the inventory and add-ons of the datacenter, and every problem found
+is reported at once.`,
 	Example: `  # Order a server with Debian 11
   ics-cli baremetal create --sku c1.small --datacenter NYC1 --os DEBIAN_11
   
   # Order a server with SSH keys and support
-  ics-cli baremetal create --sku c1.small --datacenter NYC1 --os DEBIAN_11 --ssh-keys "My Key,Work Key" --support BASICSUP`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Get required parameters
-		sku, _ := cmd.Flags().GetString("sku")
-		datacenter, _ := cmd.Flags().GetString("datacenter")
-		osCode, _ := cmd.Flags().GetString("os")
-
-		// Validate required parameters
-		if sku == "" {
-			fmt.Fprintln(os.Stderr, "Error: --sku flag is required")
-			return
-		}
+  ics-cli baremetal create --sku c1.small --datacenter NYC1 --os DEBIAN_11 --ssh-keys "My Key,Work Key" --support BASICSUP
 
-		if datacenter == "" {
-			fmt.Fprintln(os.Stderr, "Error: --datacenter flag is required")
-			return
-		}
+  # Check an order without placing it
+  ics-cli baremetal create --sku c1.small --datacenter NYC1 --os DEBIAN_11 --quantity 3 --dry-run
 
-		if osCode == "" {
-			fmt.Fprintln(os.Stderr, "Error: --os flag is required")
-			return
+  # Order a server and wait until it is ready to log in to
+  ics-cli baremetal create --sku c1.small --datacenter NYC1 --os DEBIAN_11 --ssh-keys "My Key" --wait`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		orderRequest, err := orderFromFlags(cmd)
+		if err != nil {
+			return err
 		}
 
-		// Get optional parameters
-		quantity, _ := cmd.Flags().GetInt("quantity")
-		licenseCode, _ := cmd.Flags().GetString("license")
-		bandwidthTB, _ := cmd.Flags().GetInt("bandwidth")
-		supportCode, _ := cmd.Flags().GetString("support")
 		sshKeysStr, _ := cmd.Flags().GetString("ssh-keys")
-
-		// Default quantity to 1 if not specified
-		if quantity <= 0 {
-			quantity = 1
-		}
-
-		// Build the request
-		orderRequest := OrderRequest{
-			SKUProductName:             sku,
-			Quantity:                   quantity,
-			LocationCode:               datacenter,
-			OperatingSystemProductCode: osCode,
-		}
-
-		// Add optional parameters if provided
-		if licenseCode != "" {
-			orderRequest.LicenseProductCode = licenseCode
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		wait, _ := cmd.Flags().GetBool("wait")
+		timeout, interval, err := getPollFlags(cmd)
+		if err != nil {
+			return err
 		}
 
-		if bandwidthTB > 0 {
-			orderRequest.AdditionalBandwidthTB = bandwidthTB
+		client, err := newAPIClient()
+		if err != nil {
+			return err
 		}
 
-		if supportCode != "" {
-			orderRequest.SupportLevelProductCode = supportCode
-		}
+		// Problems found before placing the order, reported together
+		var problems []string
 
 		// Process SSH keys if provided
 		if sshKeysStr != "" {
@@ -97,10 +82,13 @@ Optional parameters include:
 				}
 
 				// Look up the key ID from the label
-				key, err := getSSHKeyFromLabel(keyName)
+				key, err := client.FindSSHKeyByLabel(cmd.Context(), keyName)
+				if errors.Is(err, icsapi.ErrNotFound) {
+					problems = append(problems, fmt.Sprintf("SSH key %q not found", keyName))
+					continue
+				}
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error finding SSH key '%s': %v\n", keyName, err)
-					return
+					return fmt.Errorf("failed to find SSH key '%s': %w", keyName, err)
 				}
 
 				keyIDs = append(keyIDs, key.ID)
@@ -111,44 +99,161 @@ Optional parameters include:
 			}
 		}
 
+		// Check the order against the inventory and add-ons before anything is charged
+		catalog, err := client.GetOrderCatalog(cmd.Context(), orderRequest)
+		if err != nil {
+			return fmt.Errorf("failed to run pre-flight checks: %w", err)
+		}
+		problems = append(problems, catalog.Validate(orderRequest)...)
+
+		if len(problems) > 0 {
+			return preflightError(problems)
+		}
+
+		// The price breakdown is informative, so an order can still be placed without it
+		quote, err := catalog.Quote(orderRequest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not price the order: %v\n", err)
+		}
+
+		// Show the order without placing it
+		if dryRun {
+			if !isTableOutput() {
+				return printStructured(orderResult{ServiceIDs: []int{}, Order: orderRequest, Quote: quote, DryRun: true})
+			}
+			printOrderDetails(orderRequest)
+			if quote != nil {
+				fmt.Fprintln(os.Stderr)
+				printQuote(os.Stderr, orderRequest, quote)
+			}
+			fmt.Println(GreenText("\nPre-flight checks passed. Dry run, no order was placed."))
+			return nil
+		}
+
 		// Confirm the order details with the user
-		confirmOrder := confirmOrderDetails(orderRequest)
+		confirmOrder := confirmOrderDetails(orderRequest, quote)
 		if !confirmOrder {
-			fmt.Printf("%s", RedText("Order cancelled, you have not been charged."))
-			return
+			return abortedError("order cancelled, you have not been charged")
 		}
 
 		// Place the order
-		orderResult, err := placeOrder(orderRequest)
+		serviceIDs, err := client.PlaceOrder(cmd.Context(), orderRequest)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error placing order: %v\n", err)
-			return
+			return err
+		}
+
+		result := orderResult{ServiceIDs: serviceIDs, Order: orderRequest, Quote: quote}
+
+		if isTableOutput() {
+			fmt.Println(GreenText("\nOrder placed successfully"))
+			fmt.Printf("Service IDs: %v\n", serviceIDs)
+		}
+
+		// Wait for the servers to be provisioned, still showing the order if that fails
+		var waitErr error
+		if wait {
+			result.Servers, waitErr = waitForOrder(cmd, client, serviceIDs, timeout, interval)
 		}
 
 		// Display the order result
-		fmt.Println(GreenText("\nOrder placed successfully"))
-		fmt.Printf("Service IDs: %v", orderResult.Data.OrderServiceIDs)
-		fmt.Println("\nServices in this order will be provisioned within 60 minutes.")
+		if !isTableOutput() {
+			if err := printStructured(result); err != nil {
+				return err
+			}
+			return waitErr
+		}
+
+		if !wait {
+			fmt.Println("Services in this order will be provisioned within 60 minutes.")
+			return nil
+		}
+
+		if len(result.Servers) > 0 {
+			printNewServers(result.Servers)
+		}
+		return waitErr
 	},
 }
 
+// waitForOrder waits for the servers created by an order to appear and finish provisioning,
+// then returns their access details. If some servers are not ready before the timeout, the
+// details of the others are returned with an error.
+func waitForOrder(cmd *cobra.Command, client *icsapi.Client, serviceIDs []int, timeout, interval time.Duration) ([]newServerResult, error) {
+	deadline := time.Now().Add(timeout)
+
+	// New servers take a while to appear in the server list
+	ctx, cancel := context.WithDeadline(cmd.Context(), deadline)
+	defer cancel()
+
+	status := newProgress(fmt.Sprintf("Waiting for %d server(s) to be created...", len(serviceIDs)))
+	servers, err := client.WaitForServices(ctx, serviceIDs, interval)
+	status.Stop()
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := waitForServers(cmd.Context(), client, servers, waitConditions["provisioned"], defaultParallel, time.Until(deadline), interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []newServerResult
+	var errs []error
+	for i, server := range servers {
+		if states[i].err != nil {
+			errs = append(errs, states[i].err)
+			continue
+		}
+
+		detail, err := client.GetServer(cmd.Context(), server.ID)
+		if err != nil {
+			return results, err
+		}
+
+		keys, err := client.GetServerSSHKeys(cmd.Context(), server.ID)
+		if err != nil {
+			return results, err
+		}
+
+		labels := make([]string, len(keys))
+		for j, key := range keys {
+			labels[j] = key.Label
+		}
+
+		results = append(results, newServerResult{
+			ServiceID: detail.ServiceID,
+			Hostname:  detail.Hostname,
+			PrimaryIP: detail.PublicIP,
+			Username:  detail.OperatingSystemUsername,
+			SSHKeys:   labels,
+		})
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// printNewServers prints the access details of newly provisioned servers
+func printNewServers(servers []newServerResult) {
+	headerFmt := color.New(color.FgBlue).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	fmt.Println(GreenText("\nServers provisioned successfully"))
+	tbl := table.New("Service ID", "Hostname", "Primary IP", "Username", "SSH Keys")
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, server := range servers {
+		tbl.AddRow(server.ServiceID, server.Hostname, server.PrimaryIP, server.Username, strings.Join(server.SSHKeys, ", "))
+	}
+
+	tbl.Print()
+}
+
 func init() {
 	baremetalDeployCmd.AddCommand(bmdDeployCmd)
 
-	// Required flags
-	bmdDeployCmd.Flags().String("sku", "", "Server type/SKU (required, e.g., c1i.small)")
-	bmdDeployCmd.Flags().String("datacenter", "", "Datacenter location (required, e.g., NYC1)")
-	bmdDeployCmd.Flags().String("os", "", "Operating system product code (required, e.g., DEBIAN_11)")
-
-	// Optional flags
-	bmdDeployCmd.Flags().Int("quantity", 1, "Number of servers to order (default: 1)")
-	bmdDeployCmd.Flags().String("license", "", "License product code (e.g., CPANEL100)")
-	bmdDeployCmd.Flags().Int("bandwidth", 0, "Additional bandwidth in TB")
-	bmdDeployCmd.Flags().String("support", "", "Support level product code (e.g., BASICSUP)")
+	addOrderFlags(bmdDeployCmd)
 	bmdDeployCmd.Flags().String("ssh-keys", "", "Comma-separated list of SSH key names to assign")
-
-	// Mark required flags
-	bmdDeployCmd.MarkFlagRequired("sku")
-	bmdDeployCmd.MarkFlagRequired("datacenter")
-	bmdDeployCmd.MarkFlagRequired("os")
+	bmdDeployCmd.Flags().Bool("dry-run", false, "Run the pre-flight checks and show the order without placing it")
+	bmdDeployCmd.Flags().Bool("wait", false, "Wait until the servers are provisioned and print their access details")
+	addPollFlags(bmdDeployCmd, defaultProvisionTimeout)
 }
@@ -2,7 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -22,58 +23,73 @@ You can filter the results using various flags:
   --datacenter NYC1     Show only inventory in a specific datacenter
   --sku c1.small       Show only a specific server type
   --min-price 100       Show servers with price >= $100
-  --max-price 300       Show servers with price <= $300`,
-	Run: func(cmd *cobra.Command, args []string) {
+  --max-price 300       Show servers with price <= $300
+  --region 1            Show only inventory in a specific region
+  --cpu-brand AMD       Show only servers with a specific CPU brand
+  --min-cores 16        Show servers with at least 16 cores across all CPUs
+  --min-ram 128         Show servers with at least 128 GB of RAM
+  --min-nvme 1920       Show servers with at least 1920 GB of NVMe storage
+  --min-nic 10000       Show servers with a NIC of at least 10 Gbps
+  --raid                Show only servers with RAID (--raid=false for without)
+
+Sort the results with --sort-by price, quantity, cores, ram, nvme, storage, nic, sku or
+location (the default), and --reverse for descending order.`,
+	Example: `  # Servers with at least 16 cores and 128 GB of RAM, cheapest first
+  ics-cli baremetal deploy list-inventory --min-cores 16 --min-ram 128 --sort-by price
+
+  # AMD servers with RAID in NYC1, most NVMe storage first
+  ics-cli baremetal deploy list-inventory --datacenter NYC1 --cpu-brand AMD --raid --sort-by nvme --reverse`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get filter flags
-		datacenter, _ := cmd.Flags().GetString("datacenter")
-		sku, _ := cmd.Flags().GetString("sku")
-		minPriceStr, _ := cmd.Flags().GetString("min-price")
-		maxPriceStr, _ := cmd.Flags().GetString("max-price")
-
-		// Parse price filters
-		var minPrice, maxPrice float64
-		var err error
-
-		if minPriceStr != "" {
-			minPrice, err = strconv.ParseFloat(minPriceStr, 64)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: Invalid min-price value: %v\n", err)
-				return
-			}
+		filter, err := inventoryFilterFromFlags(cmd)
+		if err != nil {
+			return err
 		}
 
-		if maxPriceStr != "" {
-			maxPrice, err = strconv.ParseFloat(maxPriceStr, 64)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: Invalid max-price value: %v\n", err)
-				return
-			}
+		sortBy, _ := cmd.Flags().GetString("sort-by")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+
+		if _, ok := inventorySortFields[sortBy]; !ok {
+			return usageError("invalid --sort-by %q. Must be one of: %s", sortBy, strings.Join(slices.Sorted(maps.Keys(inventorySortFields)), ", "))
+		}
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
 		}
 
 		// Get the inventory from API
-		inventory, err := getInventory()
+		inventory, err := client.Inventory(cmd.Context())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting inventory: %v\n", err)
-			return
+			return err
 		}
 
 		// Apply filters
-		filteredInventory := filterInventory(inventory, datacenter, sku, minPrice, maxPrice)
+		filteredInventory := filterInventory(inventory, filter)
+		compare := inventoryCompare(sortBy, reverse)
+
+		if !isTableOutput() {
+			slices.SortStableFunc(filteredInventory, compare)
+			return printStructured(filteredInventory)
+		}
 
 		// Group the filtered inventory by location and SKU
 		groupedInventory := groupInventory(filteredInventory)
+		slices.SortStableFunc(groupedInventory, func(a, b GroupedInventory) int {
+			return compare(a.InventoryDetails, b.InventoryDetails)
+		})
 
 		// Display the grouped inventory
 		if len(groupedInventory) == 0 {
 			fmt.Println("No inventory available.")
-			return
+			return nil
 		}
 
 		headerFmt := color.New(color.FgBlue, color.Bold).SprintfFunc()
 		columnFmt := color.New(color.FgYellow).SprintfFunc()
 
 		// Create table with headers
-		tbl := table.New("Location", "Server Type", "Price (USD)", "Available Quantity")
+		tbl := table.New("Location", "Server Type", "CPU", "Cores", "RAM", "Storage", "RAID", "NIC", "Price (USD)", "Available Quantity")
 		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
 
 		// Add each item to the table
@@ -85,15 +101,32 @@ You can filter the results using various flags:
 				price = "$" + price
 			}
 
+			cores := strconv.Itoa(totalCores(item.InventoryDetails))
+			if item.CPUCount > 1 {
+				cores = fmt.Sprintf("%s (%dx%d)", cores, item.CPUCount, item.CPUCores)
+			}
+
+			raid := "No"
+			if item.RAIDEnabled {
+				raid = "Yes"
+			}
+
 			tbl.AddRow(
 				item.LocationCode,
 				item.SkuProductName,
+				strings.TrimSpace(item.CPUBrand+" "+item.CPUModel),
+				cores,
+				fmt.Sprintf("%d GB", item.TotalRAMGB),
+				formatStorage(item.InventoryDetails),
+				raid,
+				formatNICSpeed(item.NICSpeedMbps),
 				price,
 				strconv.Itoa(item.TotalQuantity),
 			)
 		}
 
 		tbl.Print()
+		return nil
 	},
 }
 
@@ -101,8 +134,9 @@ func init() {
 	baremetalDeployCmd.AddCommand(bmdInventoryCmd)
 
 	// Add filter flags
-	bmdInventoryCmd.Flags().String("datacenter", "", "Filter by datacenter (e.g., NYC1)")
-	bmdInventoryCmd.Flags().String("sku", "", "Filter by server type (e.g., c1.small)")
-	bmdInventoryCmd.Flags().String("min-price", "", "Filter by minimum price")
-	bmdInventoryCmd.Flags().String("max-price", "", "Filter by maximum price")
+	addInventoryFilterFlags(bmdInventoryCmd)
+
+	// Add sort flags
+	bmdInventoryCmd.Flags().String("sort-by", "location", "Sort by location, sku, price, quantity, cores, ram, nvme, storage or nic")
+	bmdInventoryCmd.Flags().Bool("reverse", false, "Reverse the sort order, e.g. to show the most cores first")
 }
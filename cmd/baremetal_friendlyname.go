@@ -2,55 +2,43 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"strconv"
 
 	"github.com/spf13/cobra"
 )
 
 // bmNameCmd represents the name command
 var bmNameCmd = &cobra.Command{
-	Use:   "friendlyname [serviceID]",
+	Use:   "friendlyname [server]",
 	Short: "Set a friendly name for a server",
 	Example: `  
 	# Set a friendly name for a server
 	ics-cli baremetal friendlyname 123456 --name "Production Server 1"`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		// Get service ID from arguments
-		serviceID := args[0]
-
-		// Validate service ID is a number
-		if _, err := strconv.Atoi(serviceID); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be a number")
-			return
+		client, err := newAPIClient()
+		if err != nil {
+			return err
 		}
 
-		// Step 1: Get the server ID from service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
+		// Resolve the server argument
+		server, err := client.ResolveServer(cmd.Context(), args[0])
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
 		// Get the friendly name
 		name, _ := cmd.Flags().GetString("name")
 		if name == "" {
-			fmt.Fprintln(os.Stderr, "Error: Friendly name is required with --name or -n flag")
-			return
+			return usageError("friendly name is required with --name or -n flag")
 		}
 
 		// Set the friendly name
-		customPXE, err := setFriendlyName(serverID, name)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error setting Friendly name:", err)
-			return
+		if err := client.SetFriendlyName(cmd.Context(), server.ID, name); err != nil {
+			return fmt.Errorf("failed to set friendly name: %w", err)
 		}
 
-		if customPXE {
-			fmt.Printf("%s\n", BlueHeading("Successfully updated the friendly name of the server."))
-		}
+		return printActionResult(newActionResult(server.ServiceID, "friendlyname", true, "Successfully updated the friendly name of the server."))
 	},
 }
 
@@ -1,45 +1,44 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-	"strconv"
-
 	"github.com/spf13/cobra"
 )
 
 // bmstatusCmd represents the bare metal status command
 var bmstatusCmd = &cobra.Command{
-	Use:     "get [serviceID]",
+	Use:     "get [server]",
 	Aliases: []string{"status"},
-	Short:   "Get the information of a Baremetal Server by its Service ID",
+	Short:   "Get the information of a Baremetal Server",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-
-		// Get service ID from arguments
-		serviceID := args[0]
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		// Validate service ID is a number
-		if _, err := strconv.Atoi(serviceID); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be a number")
-			return
+		client, err := newAPIClient()
+		if err != nil {
+			return err
 		}
 
-		// Step 1: Get the server ID from service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
+		// Resolve the server argument
+		server, err := client.ResolveServer(cmd.Context(), args[0])
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
-		// Step 2: Get server details
-		server, err := getServerDetails(serverID)
+		// Get server details
+		detail, err := client.GetServer(cmd.Context(), server.ID)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
+		}
+
+		if !isTableOutput() {
+			output, err := getServerDetailOutput(cmd, client, detail)
+			if err != nil {
+				return err
+			}
+			return printStructured(output)
 		}
 
-		printServerDetails(cmd, server)
+		printServerDetails(cmd, client, detail)
+		return nil
 	},
 }
 
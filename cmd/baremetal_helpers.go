@@ -1,179 +1,21 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
+	"cmp"
 	"fmt"
-	"sort"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 )
 
-// getServerIDFromServiceID finds the server ID corresponding to a service ID
-func getServerIDFromServiceID(serviceID string) (string, error) {
-	// Use makeAPIRequest to get the server list
-	var serverList ServerResponse
-
-	err := makeAPIRequest(
-		"GET",
-		30,
-		"https://api.ingenuitycloudservices.com/rest-api/servers",
-		nil,
-		&serverList,
-	)
-
-	if err != nil {
-		return "", fmt.Errorf("failed to get server list: %w", err)
-	}
-
-	// Find the server with the matching service ID
-	for _, server := range serverList.Data {
-		if strconv.Itoa(server.ServiceID) == serviceID {
-			return server.ID, nil
-		}
-	}
-
-	return "", fmt.Errorf("server with Service ID %s not found", serviceID)
-}
-
-// getSolResponse gets the SOL Link to a server
-func getIkvmResponse(serverID string) (string, error) {
-	var response RemoteAccessResponse
-
-	err := makeAPIRequest(
-		"POST",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/remote-access/ikvm", serverID),
-		nil,
-		&response,
-	)
-
-	if err != nil {
-		return "", fmt.Errorf("%w", err)
-	}
-
-	return response.Data.Redirect, nil
-}
-
-// getSolResponse gets the SOL Link to a server
-func setPXEUrl(serverID, url string) (bool, error) {
-	var response RemoteAccessResponse
-
-	// Pass url as pxe_script_url in the request body
-	requestData := map[string]string{
-		"pxe_script_url": url,
-	}
-
-	requestBody, err := json.Marshal(requestData)
-	if err != nil {
-		return false, fmt.Errorf("error creating request body: %w", err)
-	}
-
-	err = makeAPIRequest(
-		"PUT",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/set-pxe", serverID),
-		bytes.NewBuffer(requestBody),
-		&response,
-	)
-
-	if err != nil {
-		return false, fmt.Errorf("%w", err)
-	}
-
-	return true, nil
-}
-
-// setFriendlyName sets the frienly name of a server
-func setFriendlyName(serverID, name string) (bool, error) {
-	var response RemoteAccessResponse
-
-	// Pass friendly name
-	requestData := map[string]string{
-		"friendly_name": name,
-	}
-
-	requestBody, err := json.Marshal(requestData)
-	if err != nil {
-		return false, fmt.Errorf("error creating request body: %w", err)
-	}
-
-	err = makeAPIRequest(
-		"PUT",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/friendly-name", serverID),
-		bytes.NewBuffer(requestBody),
-		&response,
-	)
-
-	if err != nil {
-		return false, fmt.Errorf("%w", err)
-	}
-
-	return true, nil
-}
-
-// getServerDetails gets detailed information about a server
-func getServerDetails(serverID string) (*ServerDetail, error) {
-	var response ServerDetailResponse
-
-	err := makeAPIRequest(
-		"GET",
-		30,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s", serverID),
-		nil,
-		&response,
-	)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get server details: %w", err)
-	}
-
-	return &response.Data, nil
-}
-
-// getPowerStatus gets the power status of a server
-func getPowerStatus(serverID int) (bool, error) {
-	var response PowerStatusResponse
-
-	err := makeAPIRequest(
-		"GET",
-		30,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%d/power/status", serverID),
-		nil,
-		&response,
-	)
-
-	if err != nil {
-		return false, fmt.Errorf("failed to get power status: %w", err)
-	}
-
-	return response.Data.IsPoweredOn, nil
-}
-
-// getServerSSHKeys gets the SSH keys assigned to a server
-func getServerSSHKeys(serverID int) ([]AssignedSSHKey, error) {
-	var keysResponse AssignedSSHKeysResponse
-
-	err := makeAPIRequest(
-		"GET",
-		30,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%d/ssh-keys", serverID),
-		nil,
-		&keysResponse,
-	)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get SSH keys: %w", err)
-	}
-
-	return keysResponse.Data, nil
-}
-
-// Now update the printServerDetails function to include SSH keys
-func printServerDetails(cmd *cobra.Command, server *ServerDetail) {
+// printServerDetails prints the details, SSH keys and power status of a server
+func printServerDetails(cmd *cobra.Command, client *icsapi.Client, server *icsapi.ServerDetail) {
 
 	// General Information Section
 	//fmt.Println(blue("=== SERVER INFORMATION ==="))
@@ -182,6 +24,9 @@ func printServerDetails(cmd *cobra.Command, server *ServerDetail) {
 	fmt.Printf("%s %s\n", BlueHeading("Friendly Name:"), WhiteText(server.FriendlyName))
 	fmt.Printf("%s %s\n", BlueHeading("Datacenter:"), WhiteText(server.DatacenterName))
 	fmt.Printf("%s %s\n", BlueHeading("Operating System:"), WhiteText(server.OperatingSystemName))
+	if server.ProvisioningStatus.IsProvisioning {
+		fmt.Printf("%s %s\n", BlueHeading("Provisioning:"), YellowText(server.ProvisioningStatus.StatusMessage))
+	}
 
 	// Network Information Section
 	fmt.Println(BlueHeading("\n=== NETWORK INFORMATION ==="))
@@ -206,7 +51,7 @@ func printServerDetails(cmd *cobra.Command, server *ServerDetail) {
 	fmt.Printf("%s %s\n", BlueHeading("Password:"), WhiteText(server.OperatingSystemPassword))
 
 	// Add SSH Keys section
-	sshKeys, err := getServerSSHKeys(server.ServerID)
+	sshKeys, err := client.GetServerSSHKeys(cmd.Context(), strconv.Itoa(server.ServerID))
 	if err == nil && len(sshKeys) > 0 {
 		// Create a slice to hold the key labels
 		keyLabels := make([]string, len(sshKeys))
@@ -228,7 +73,7 @@ func printServerDetails(cmd *cobra.Command, server *ServerDetail) {
 		// Power Status Section
 		fmt.Println(BlueHeading("\n=== POWER STATUS ==="))
 
-		isPoweredOn, err := getPowerStatus(server.ServerID)
+		isPoweredOn, err := client.PowerStatus(cmd.Context(), strconv.Itoa(server.ServerID))
 		if err != nil {
 			fmt.Printf("%s %s\n", BlueHeading("Power State:"), RedText("UNKNOWN"))
 		} else {
@@ -241,359 +86,503 @@ func printServerDetails(cmd *cobra.Command, server *ServerDetail) {
 	}
 }
 
-// Get server list
-func getServerList() ([]Server, error) {
-	var serverResp ServerResponse
-
-	err := makeAPIRequest(
-		"GET",
-		30,
-		"https://api.ingenuitycloudservices.com/rest-api/servers",
-		nil, // no request body for GET
-		&serverResp,
-	)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get server list: %w", err)
-	}
-
-	return serverResp.Data, nil
+// serverDetailOutput is the machine readable output of a server's details
+type serverDetailOutput struct {
+	*icsapi.ServerDetail
+	SSHKeys     []icsapi.AssignedSSHKey `json:"ssh_keys"`                // SSH keys assigned to the server
+	IsPoweredOn *bool                   `json:"is_powered_on,omitempty"` // Power state, unless skipped with --power
 }
 
-// setPowerOff sends a power off
-func setPowerOff(serverID string) (bool, error) {
-	var response GenericServerResponse
+// getServerDetailOutput collects the details, SSH keys and power status of a server
+func getServerDetailOutput(cmd *cobra.Command, client *icsapi.Client, server *icsapi.ServerDetail) (serverDetailOutput, error) {
+	output := serverDetailOutput{ServerDetail: server}
 
-	err := makeAPIRequest(
-		"POST",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/power/off", serverID),
-		nil,
-		&response,
-	)
+	// Redact the root password by default
+	showRootPassword, _ := cmd.Flags().GetBool("password")
+	if !showRootPassword {
+		server.OperatingSystemPassword = "********"
+	}
 
+	sshKeys, err := client.GetServerSSHKeys(cmd.Context(), strconv.Itoa(server.ServerID))
 	if err != nil {
-		return false, fmt.Errorf("%w", err)
+		return output, err
 	}
+	output.SSHKeys = sshKeys
 
-	// Check for success
-	if !response.Data.Success {
-		return false, nil
+	hidePowerStatus, _ := cmd.Flags().GetBool("power")
+	if !hidePowerStatus {
+		isPoweredOn, err := client.PowerStatus(cmd.Context(), strconv.Itoa(server.ServerID))
+		if err != nil {
+			return output, err
+		}
+		output.IsPoweredOn = &isPoweredOn
 	}
 
-	return true, nil
+	return output, nil
 }
 
-// setPowerOn sends a power on
-func setPowerOn(serverID string) (bool, error) {
-	var response GenericServerResponse
+// inventoryFilter holds the list-inventory filters. Zero values do not filter.
+type inventoryFilter struct {
+	datacenter string  // Location code, e.g. NYC1
+	region     int     // Region ID
+	sku        string  // Server type
+	minPrice   float64 // Minimum monthly price
+	maxPrice   float64 // Maximum monthly price
+	cpuBrand   string  // CPU brand, e.g. AMD
+	minCores   int     // Minimum total CPU cores
+	minRAM     int     // Minimum RAM in GB
+	minNVMe    int     // Minimum NVMe storage in GB
+	minNIC     int     // Minimum NIC speed in Mbps
+	raid       *bool   // Whether RAID must be enabled or not, nil for either
+}
 
-	err := makeAPIRequest(
-		"POST",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/power/on", serverID),
-		nil,
-		&response,
-	)
+// addInventoryFilterFlags adds the flags filtering the inventory to a command
+func addInventoryFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("datacenter", "", "Filter by datacenter (e.g., NYC1)")
+	cmd.Flags().Int("region", 0, "Filter by region ID")
+	cmd.Flags().String("sku", "", "Filter by server type (e.g., c1.small)")
+	cmd.Flags().String("min-price", "", "Filter by minimum price")
+	cmd.Flags().String("max-price", "", "Filter by maximum price")
+	cmd.Flags().String("cpu-brand", "", "Filter by CPU brand (e.g., AMD)")
+	cmd.Flags().Int("min-cores", 0, "Filter by minimum number of CPU cores, across all CPUs")
+	cmd.Flags().Int("min-ram", 0, "Filter by minimum RAM in GB")
+	cmd.Flags().Int("min-nvme", 0, "Filter by minimum NVMe storage in GB")
+	cmd.Flags().Int("min-nic", 0, "Filter by minimum NIC speed in Mbps (e.g., 10000)")
+	cmd.Flags().Bool("raid", false, "Filter by RAID support (--raid or --raid=false)")
+}
 
-	if err != nil {
-		return false, fmt.Errorf("%w", err)
+// inventoryFilterFromFlags returns the filter set by the flags added by addInventoryFilterFlags
+func inventoryFilterFromFlags(cmd *cobra.Command) (inventoryFilter, error) {
+	var filter inventoryFilter
+	filter.datacenter, _ = cmd.Flags().GetString("datacenter")
+	filter.region, _ = cmd.Flags().GetInt("region")
+	filter.sku, _ = cmd.Flags().GetString("sku")
+	filter.cpuBrand, _ = cmd.Flags().GetString("cpu-brand")
+	filter.minCores, _ = cmd.Flags().GetInt("min-cores")
+	filter.minRAM, _ = cmd.Flags().GetInt("min-ram")
+	filter.minNVMe, _ = cmd.Flags().GetInt("min-nvme")
+	filter.minNIC, _ = cmd.Flags().GetInt("min-nic")
+	minPriceStr, _ := cmd.Flags().GetString("min-price")
+	maxPriceStr, _ := cmd.Flags().GetString("max-price")
+
+	// Only filter on RAID when the flag is given, so --raid=false finds servers without it
+	if cmd.Flags().Changed("raid") {
+		raid, _ := cmd.Flags().GetBool("raid")
+		filter.raid = &raid
+	}
+
+	// Parse price filters
+	var err error
+
+	if minPriceStr != "" {
+		filter.minPrice, err = strconv.ParseFloat(minPriceStr, 64)
+		if err != nil {
+			return filter, usageError("invalid min-price value: %v", err)
+		}
 	}
 
-	// Check for success
-	if !response.Data.Success {
-		return false, nil
+	if maxPriceStr != "" {
+		filter.maxPrice, err = strconv.ParseFloat(maxPriceStr, 64)
+		if err != nil {
+			return filter, usageError("invalid max-price value: %v", err)
+		}
 	}
 
-	return true, nil
+	return filter, nil
 }
 
-// setReboot sends a power off
-func setReboot(serverID string) (bool, error) {
-	var response GenericServerResponse
-
-	err := makeAPIRequest(
-		"POST",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/power/reboot", serverID),
-		nil,
-		&response,
-	)
+// filterInventory applies filters to the inventory data
+func filterInventory(inventory []icsapi.InventoryDetails, filter inventoryFilter) []icsapi.InventoryDetails {
+	filtered := make([]icsapi.InventoryDetails, 0)
 
-	if err != nil {
-		return false, fmt.Errorf("%w", err)
-	}
+	for _, item := range inventory {
+		// Filter by datacenter
+		if filter.datacenter != "" && !strings.EqualFold(item.LocationCode, filter.datacenter) {
+			continue
+		}
 
-	// Check for success
-	if !response.Data.Success {
-		return false, nil
-	}
+		// Filter by region
+		if filter.region != 0 && item.RegionID != filter.region {
+			continue
+		}
 
-	return true, nil
-}
+		// Filter by SKU
+		if filter.sku != "" && !strings.EqualFold(item.SkuProductName, filter.sku) {
+			continue
+		}
 
-// setRecovery boots to Rescue
-func setRecovery(serverID string) (bool, error) {
-	var response GenericServerResponse
+		// Filter by price
+		itemPrice, err := strconv.ParseFloat(item.Price, 64)
+		if err == nil {
+			if filter.minPrice > 0 && itemPrice < filter.minPrice {
+				continue
+			}
+			if filter.maxPrice > 0 && itemPrice > filter.maxPrice {
+				continue
+			}
+		}
 
-	err := makeAPIRequest(
-		"POST",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/recovery/reboot", serverID),
-		nil,
-		&response,
-	)
+		// Filter by hardware
+		if filter.cpuBrand != "" && !strings.EqualFold(item.CPUBrand, filter.cpuBrand) {
+			continue
+		}
+		if totalCores(item) < filter.minCores || item.TotalRAMGB < filter.minRAM || item.TotalNVMESizeGB < filter.minNVMe || item.NICSpeedMbps < filter.minNIC {
+			continue
+		}
+		if filter.raid != nil && item.RAIDEnabled != *filter.raid {
+			continue
+		}
 
-	if err != nil {
-		return false, fmt.Errorf("%w", err)
+		// This item passed all filters, include it
+		filtered = append(filtered, item)
 	}
 
-	// Check for success
-	if !response.Data.Success {
-		return false, nil
-	}
+	return filtered
+}
 
-	return true, nil
+// totalCores returns the number of CPU cores of a server, across all its CPUs
+func totalCores(item icsapi.InventoryDetails) int {
+	return item.CPUCores * max(item.CPUCount, 1)
 }
 
-// getSolResponse gets the SOL Link to a server
-func getSolResponse(serverID string) (string, error) {
-	var response RemoteAccessResponse
+// inventoryPrice returns the monthly price of an inventory item, or 0 if it is not a number
+func inventoryPrice(item icsapi.InventoryDetails) float64 {
+	price, _ := strconv.ParseFloat(item.Price, 64)
+	return price
+}
 
-	err := makeAPIRequest(
-		"POST",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/remote-access/sol", serverID),
-		nil,
-		&response,
-	)
+// inventorySortFields are the values of the list-inventory --sort-by flag
+var inventorySortFields = map[string]func(a, b icsapi.InventoryDetails) int{
+	"location": func(a, b icsapi.InventoryDetails) int {
+		return cmp.Or(cmp.Compare(a.LocationCode, b.LocationCode), cmp.Compare(a.SkuProductName, b.SkuProductName))
+	},
+	"sku": func(a, b icsapi.InventoryDetails) int {
+		return cmp.Or(cmp.Compare(a.SkuProductName, b.SkuProductName), cmp.Compare(a.LocationCode, b.LocationCode))
+	},
+	"price":    func(a, b icsapi.InventoryDetails) int { return cmp.Compare(inventoryPrice(a), inventoryPrice(b)) },
+	"quantity": func(a, b icsapi.InventoryDetails) int { return cmp.Compare(a.Quantity, b.Quantity) },
+	"cores":    func(a, b icsapi.InventoryDetails) int { return cmp.Compare(totalCores(a), totalCores(b)) },
+	"ram":      func(a, b icsapi.InventoryDetails) int { return cmp.Compare(a.TotalRAMGB, b.TotalRAMGB) },
+	"nvme":     func(a, b icsapi.InventoryDetails) int { return cmp.Compare(a.TotalNVMESizeGB, b.TotalNVMESizeGB) },
+	"storage":  func(a, b icsapi.InventoryDetails) int { return cmp.Compare(totalStorage(a), totalStorage(b)) },
+	"nic":      func(a, b icsapi.InventoryDetails) int { return cmp.Compare(a.NICSpeedMbps, b.NICSpeedMbps) },
+}
 
-	if err != nil {
-		return "", fmt.Errorf("%w", err)
+// inventoryCompare returns a comparison of inventory by a --sort-by field, ordering equal
+// items by location and SKU
+func inventoryCompare(field string, reverse bool) func(a, b icsapi.InventoryDetails) int {
+	compare := inventorySortFields[field]
+	return func(a, b icsapi.InventoryDetails) int {
+		if reverse {
+			a, b = b, a
+		}
+		return cmp.Or(compare(a, b), inventorySortFields["location"](a, b))
 	}
-
-	return response.Data.Redirect, nil
 }
 
-// getOSList retrieves the list of available operating systems for a server
-func getOSList(serverID string) ([]OS, error) {
-	var response OSListResponse
-
-	err := makeAPIRequest(
-		"GET",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/provision/os-list", serverID),
-		nil,
-		&response,
-	)
+// totalStorage returns the storage of a server in GB, across all drive types
+func totalStorage(item icsapi.InventoryDetails) int {
+	return item.TotalNVMESizeGB + item.TotalSSDSizeGB + item.TotalHDDSizeGB
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to get OS list: %w", err)
+// formatStorage describes the storage of a server, e.g. "960 GB NVMe + 4000 GB HDD"
+func formatStorage(item icsapi.InventoryDetails) string {
+	var parts []string
+	for _, drive := range []struct {
+		size int
+		kind string
+	}{{item.TotalNVMESizeGB, "NVMe"}, {item.TotalSSDSizeGB, "SSD"}, {item.TotalHDDSizeGB, "HDD"}} {
+		if drive.size > 0 {
+			parts = append(parts, fmt.Sprintf("%d GB %s", drive.size, drive.kind))
+		}
 	}
-
-	return response.Data.OSList, nil
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " + ")
 }
 
-// setPowerOff sends a power off
-func setReinstallOS(serverID, reason, imageId string) (bool, error) {
-	var response GenericServerResponse
-
-	requestData := map[string]string{
-		"os_image_id": imageId,
-		"reason":      reason,
+// formatNICSpeed describes a NIC speed, e.g. "10 Gbps"
+func formatNICSpeed(mbps int) string {
+	if mbps >= 1000 && mbps%1000 == 0 {
+		return fmt.Sprintf("%d Gbps", mbps/1000)
 	}
+	return fmt.Sprintf("%d Mbps", mbps)
+}
 
-	requestBody, err := json.Marshal(requestData)
-	if err != nil {
-		return false, fmt.Errorf("error creating request body: %w", err)
-	}
+// printAddons prints tables of the operating systems, licenses and support levels of a server type
+func printAddons(addons *icsapi.AddonTypes) {
+	// Display the add-ons in tables
+	headerFmt := color.New(color.FgBlue, color.Bold).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+	headerText := color.New(color.FgGreen, color.Bold).SprintfFunc()
+
+	// Display Operating Systems
+	if len(addons.OperatingSystems.Products) > 0 {
+		fmt.Println(headerText("\n%s", addons.OperatingSystems.Name))
+
+		// Create table for Operating Systems
+		osTbl := table.New("Name", "Type", "Product Code", "Price")
+		osTbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+		// Add each OS to the table
+		for _, os := range addons.OperatingSystems.Products {
+			// Format price
+			priceStr := "Free"
+			if os.Price > 0 {
+				priceStr = "$" + fmt.Sprintf("%.2f", os.Price) + " /mo"
+			}
 
-	err = makeAPIRequest(
-		"POST",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/servers/%s/provision/reload-os", serverID),
-		bytes.NewBuffer(requestBody),
-		&response,
-	)
+			// Check if price is per core
+			if os.PricePerCore != nil {
+				if perCore, ok := os.PricePerCore.(float64); ok && perCore > 0 {
+					priceStr = fmt.Sprintf("$%.2f per core", perCore) + " /mo"
+				}
+			}
 
-	if err != nil {
-		return false, fmt.Errorf("%w", err)
-	}
+			osTbl.AddRow(
+				os.Name,
+				os.OSType,
+				os.ProductCode,
+				priceStr,
+			)
+		}
 
-	// Check for success
-	if !response.Data.Success {
-		return false, nil
+		osTbl.Print()
 	}
 
-	return true, nil
-}
-
-// getInventory retrieves server inventory from the API
-func getInventory() ([]InventoryDetails, error) {
-	var response InventoryResponse
+	// Display Licenses
+	if len(addons.Licenses.Products) > 0 {
+		fmt.Println(headerText("\n%s", addons.Licenses.Name))
 
-	err := makeAPIRequest(
-		"GET",
-		60,
-		"https://api.ingenuitycloudservices.com/rest-api/server-orders/inventory",
-		nil,
-		&response,
-	)
+		// Create table for Licenses
+		licTbl := table.New("Name", "Product Code", "Price")
+		licTbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to get inventory: %w", err)
-	}
+		// Add each license to the table
+		for _, lic := range addons.Licenses.Products {
+			// Format price
+			priceStr := "Free"
+			if lic.Price > 0 {
+				priceStr = "$" + fmt.Sprintf("%.2f", lic.Price) + " /mo"
+			}
 
-	return response.Data, nil
-}
+			licTbl.AddRow(
+				lic.Name,
+				lic.ProductCode,
+				priceStr,
+			)
+		}
 
-// filterInventory applies filters to the inventory data
-func filterInventory(inventory []InventoryDetails, datacenter, sku string, minPrice, maxPrice float64) []InventoryDetails {
-	filtered := make([]InventoryDetails, 0)
+		licTbl.Print()
+	}
 
-	for _, item := range inventory {
-		// Filter by datacenter
-		if datacenter != "" && !strings.EqualFold(item.LocationCode, datacenter) {
-			continue
-		}
+	// Display Support Levels
+	if len(addons.SupportLevels.Products) > 0 {
+		fmt.Println(headerText("\n%s", addons.SupportLevels.Name))
 
-		// Filter by SKU
-		if sku != "" && !strings.EqualFold(item.SkuProductName, sku) {
-			continue
-		}
+		// Create table for Support Levels
+		supTbl := table.New("Name", "Description", "Product Code", "Price")
+		supTbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
 
-		// Filter by price
-		itemPrice, err := strconv.ParseFloat(item.Price, 64)
-		if err == nil {
-			if minPrice > 0 && itemPrice < minPrice {
-				continue
-			}
-			if maxPrice > 0 && itemPrice > maxPrice {
-				continue
+		// Add each support level to the table
+		for _, sup := range addons.SupportLevels.Products {
+			// Format price
+			priceStr := "Free"
+			if sup.Price > 0 {
+				priceStr = "$" + fmt.Sprintf("%.2f", sup.Price) + " /mo"
 			}
+
+			supTbl.AddRow(
+				sup.Name,
+				sup.Description,
+				sup.ProductCode,
+				priceStr,
+			)
 		}
 
-		// This item passed all filters, include it
-		filtered = append(filtered, item)
+		supTbl.Print()
 	}
+}
 
-	return filtered
+// GroupedInventory represents inventory grouped by location and SKU
+type GroupedInventory struct {
+	icsapi.InventoryDetails // Hardware and price of the SKU, with the quantity of the whole group
+	TotalQuantity           int
 }
 
-// groupInventory groups inventory by location and SKU
-func groupInventory(inventory []InventoryDetails) []GroupedInventory {
-	// Create a map to store grouped inventory
-	groups := make(map[string]GroupedInventory)
+// groupInventory groups inventory by location and SKU, keeping the order of the first item of each group
+func groupInventory(inventory []icsapi.InventoryDetails) []GroupedInventory {
+	// Create a map to find the group of an item
+	groups := make(map[string]int)
+	var result []GroupedInventory
 
 	// Group the inventory
 	for _, item := range inventory {
 		// Create a key by combining location and SKU
 		key := item.LocationCode + ":" + item.SkuProductName
 
-		if group, exists := groups[key]; exists {
+		if i, exists := groups[key]; exists {
 			// If the group exists, add to the quantity
-			group.TotalQuantity += item.Quantity
-			groups[key] = group
+			result[i].TotalQuantity += item.Quantity
+			result[i].Quantity = result[i].TotalQuantity
 		} else {
 			// Create a new group
-			groups[key] = GroupedInventory{
-				LocationCode:   item.LocationCode,
-				SkuProductName: item.SkuProductName,
-				Price:          item.Price,
-				TotalQuantity:  item.Quantity,
-			}
+			groups[key] = len(result)
+			result = append(result, GroupedInventory{InventoryDetails: item, TotalQuantity: item.Quantity})
 		}
 	}
 
-	// Convert the map to a slice for sorting
-	result := make([]GroupedInventory, 0, len(groups))
-	for _, group := range groups {
-		result = append(result, group)
+	return result
+}
+
+// confirmOrderDetails displays the order details and asks for confirmation.
+// The prompt is written to stderr so stdout only carries the order result.
+func confirmOrderDetails(order icsapi.OrderRequest, quote *icsapi.Quote) bool {
+	printOrderDetails(order)
+	if quote != nil {
+		fmt.Fprintln(os.Stderr)
+		printQuote(os.Stderr, order, quote)
 	}
 
-	// Sort by location, then by SKU
-	sort.Slice(result, func(i, j int) bool {
-		if result[i].LocationCode != result[j].LocationCode {
-			return result[i].LocationCode < result[j].LocationCode
-		}
-		return result[i].SkuProductName < result[j].SkuProductName
-	})
+	fmt.Fprintf(os.Stderr, "%s", BlueHeading("\nAre you sure you want to place this order? (y/N):"))
+	var response string
+	fmt.Scanln(&response)
 
-	return result
+	return strings.ToLower(response) == "y" || strings.ToLower(response) == "yes"
 }
 
-// getAddons retrieves available add-ons for a server type in a datacenter
-func getAddons(sku, datacenter string) (AddonsResponse, error) {
-	var response AddonsResponse
+// printOrderDetails prints the details of an order to stderr
+func printOrderDetails(order icsapi.OrderRequest) {
+	fmt.Fprintln(os.Stderr, BlueHeading("=== Order Details ==="))
+	fmt.Fprintf(os.Stderr, "%s %s\n", BlueHeading("Server Type:"), WhiteText(order.SKUProductName))
+	fmt.Fprintf(os.Stderr, "%s %s\n", BlueHeading("Datacenter:"), WhiteText(order.LocationCode))
+	fmt.Fprintf(os.Stderr, "%s %s\n", BlueHeading("Operating System:"), WhiteText(order.OperatingSystemProductCode))
+	fmt.Fprintf(os.Stderr, "%s %d\n", BlueHeading("Quantity:"), (order.Quantity))
 
-	err := makeAPIRequest(
-		"GET",
-		60,
-		fmt.Sprintf("https://api.ingenuitycloudservices.com/rest-api/server-orders/list-addons?sku_product_name=%s&location_code=%s", sku, datacenter),
-		nil,
-		&response,
-	)
+	if order.LicenseProductCode != "" {
+		fmt.Fprintf(os.Stderr, "%s %s\n", BlueHeading("License:"), WhiteText(order.LicenseProductCode))
+	}
 
-	if err != nil {
-		return AddonsResponse{}, fmt.Errorf("failed to get add-ons: %w", err)
+	if order.AdditionalBandwidthTB > 0 {
+		fmt.Fprintf(os.Stderr, "%s %d TB\n", BlueHeading("Additional Bandwidth:"), (order.AdditionalBandwidthTB))
+	}
+
+	if order.SupportLevelProductCode != "" {
+		fmt.Fprintf(os.Stderr, "%s %s\n", BlueHeading("Support Level:"), WhiteText(order.SupportLevelProductCode))
 	}
 
-	return response, nil
+	if len(order.SSHKeyIDs) > 0 {
+		fmt.Fprintf(os.Stderr, "%s %d\n", BlueHeading("SSH Keys:"), (order.SSHKeyIDs))
+	}
 }
 
-// placeOrder sends the order request to the API
-func placeOrder(order OrderRequest) (OrderResponse, error) {
-	var response OrderResponse
+// quoteItemNames are the names of the items of a quote
+var quoteItemNames = map[string]string{
+	"server":           "Server",
+	"operating_system": "Operating System",
+	"license":          "License",
+	"support":          "Support Level",
+}
 
-	// Create request body
-	requestBody, err := json.Marshal(order)
-	if err != nil {
-		return OrderResponse{}, fmt.Errorf("error creating request body: %w", err)
+// printQuote prints the monthly price breakdown of an order
+func printQuote(w io.Writer, order icsapi.OrderRequest, quote *icsapi.Quote) {
+	fmt.Fprintln(w, BlueHeading(fmt.Sprintf("=== Monthly Price (%s) ===", quote.Currency)))
+	for _, line := range quote.Lines {
+		fmt.Fprintf(w, "%s %s\n", BlueHeading(fmt.Sprintf("%s (%s):", quoteItemNames[line.Item], line.Description)), WhiteText(fmt.Sprintf("%.2f", line.Price)))
 	}
 
-	// Make API request
-	err = makeAPIRequest(
-		"POST",
-		60,
-		"https://api.ingenuitycloudservices.com/rest-api/server-orders/order",
-		bytes.NewBuffer(requestBody),
-		&response,
-	)
+	fmt.Fprintf(w, "%s %s\n", BlueHeading("Per Server:"), WhiteText(fmt.Sprintf("%.2f", quote.PerServer)))
+	servers := "servers"
+	if quote.Quantity == 1 {
+		servers = "server"
+	}
+	fmt.Fprintf(w, "%s %s\n", BlueHeading(fmt.Sprintf("Total (%d %s):", quote.Quantity, servers)), GreenText(fmt.Sprintf("%.2f %s", quote.Total, quote.Currency)))
 
-	if err != nil {
-		return OrderResponse{}, fmt.Errorf("failed to place order: %w", err)
+	if order.AdditionalBandwidthTB > 0 {
+		fmt.Fprintln(w, YellowText(fmt.Sprintf("Additional bandwidth (%d TB) is billed separately and not included.", order.AdditionalBandwidthTB)))
 	}
+}
 
-	return response, nil
+// addOrderFlags adds the flags describing an order to a command
+func addOrderFlags(cmd *cobra.Command) {
+	// Required flags
+	cmd.Flags().String("sku", "", "Server type/SKU (required, e.g., c1i.small)")
+	cmd.Flags().String("datacenter", "", "Datacenter location (required unless the site config key is set, e.g., NYC1)")
+	cmd.Flags().String("os", "", "Operating system product code (required, e.g., DEBIAN_11)")
+
+	// Optional flags
+	cmd.Flags().Int("quantity", 1, "Number of servers to order (default: 1)")
+	cmd.Flags().String("license", "", "License product code (e.g., CPANEL100)")
+	cmd.Flags().Int("bandwidth", 0, "Additional bandwidth in TB")
+	cmd.Flags().String("support", "", "Support level product code (e.g., BASICSUP)")
+
+	// Mark required flags
+	cmd.MarkFlagRequired("sku")
+	cmd.MarkFlagRequired("os")
 }
 
-// confirmOrderDetails displays the order details and asks for confirmation
-func confirmOrderDetails(order OrderRequest) bool {
-	fmt.Println(BlueHeading("=== Order Details ==="))
-	fmt.Printf("%s %s\n", BlueHeading("Server Type:"), WhiteText(order.SKUProductName))
-	fmt.Printf("%s %s\n", BlueHeading("Datacenter:"), WhiteText(order.LocationCode))
-	fmt.Printf("%s %s\n", BlueHeading("Operating System:"), WhiteText(order.OperatingSystemProductCode))
-	fmt.Printf("%s %d\n", BlueHeading("Quantity:"), (order.Quantity))
+// orderFromFlags builds an order from the flags added by addOrderFlags
+func orderFromFlags(cmd *cobra.Command) (icsapi.OrderRequest, error) {
+	// The datacenter defaults to the site config key
+	if err := applyConfigDefault(cmd, "datacenter", "site"); err != nil {
+		return icsapi.OrderRequest{}, err
+	}
 
-	if order.LicenseProductCode != "" {
-		fmt.Printf("%s %s\n", BlueHeading("License:"), WhiteText(order.LicenseProductCode))
+	// Get required parameters
+	sku, _ := cmd.Flags().GetString("sku")
+	datacenter, _ := cmd.Flags().GetString("datacenter")
+	osCode, _ := cmd.Flags().GetString("os")
+
+	// Validate required parameters
+	if sku == "" {
+		return icsapi.OrderRequest{}, usageError("--sku flag is required")
 	}
 
-	if order.AdditionalBandwidthTB > 0 {
-		fmt.Printf("%s %d TB\n", BlueHeading("Additional Bandwidth:"), (order.AdditionalBandwidthTB))
+	if datacenter == "" {
+		return icsapi.OrderRequest{}, usageError("--datacenter flag is required, or set a default with 'ics-cli config set site NYC1'")
 	}
 
-	if order.SupportLevelProductCode != "" {
-		fmt.Printf("%s %s\n", BlueHeading("Support Level:"), WhiteText(order.SupportLevelProductCode))
+	if osCode == "" {
+		return icsapi.OrderRequest{}, usageError("--os flag is required")
 	}
 
-	if len(order.SSHKeyIDs) > 0 {
-		fmt.Printf("%s %d\n", BlueHeading("SSH Keys:"), (order.SSHKeyIDs))
+	// Get optional parameters
+	quantity, _ := cmd.Flags().GetInt("quantity")
+	licenseCode, _ := cmd.Flags().GetString("license")
+	bandwidthTB, _ := cmd.Flags().GetInt("bandwidth")
+	supportCode, _ := cmd.Flags().GetString("support")
+
+	// Default quantity to 1 if not specified
+	if quantity <= 0 {
+		quantity = 1
 	}
 
-	fmt.Printf("%s", BlueHeading("\nAre you sure you want to place this order? (y/N):"))
-	var response string
-	fmt.Scanln(&response)
+	// Build the request
+	orderRequest := icsapi.OrderRequest{
+		SKUProductName:             sku,
+		Quantity:                   quantity,
+		LocationCode:               datacenter,
+		OperatingSystemProductCode: osCode,
+	}
 
-	return strings.ToLower(response) == "y" || strings.ToLower(response) == "yes"
+	// Add optional parameters if provided
+	if licenseCode != "" {
+		orderRequest.LicenseProductCode = licenseCode
+	}
+
+	if bandwidthTB > 0 {
+		orderRequest.AdditionalBandwidthTB = bandwidthTB
+	}
+
+	if supportCode != "" {
+		orderRequest.SupportLevelProductCode = supportCode
+	}
+
+	return orderRequest, nil
+}
+
+// preflightError returns the error for an order that failed its pre-flight checks
+func preflightError(problems []string) error {
+	return rejectedError("the order failed pre-flight checks, you have not been charged:\n  - %s", strings.Join(problems, "\n  - "))
 }
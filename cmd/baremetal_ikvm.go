@@ -3,53 +3,53 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strconv"
 
 	"github.com/spf13/cobra"
 )
 
 // ikvmCmd represents the ikvm command
 var ikvmCmd = &cobra.Command{
-	Use:   "ikvm [serviceID]",
+	Use:   "ikvm [server]",
 	Short: "Generate an IPMI IKVM Console URL",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		// Get service ID from arguments
-		serviceID := args[0]
-
-		// Validate service ID is a number
-		if _, err := strconv.Atoi(serviceID); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be a number")
-			return
+		client, err := newAPIClient()
+		if err != nil {
+			return err
 		}
 
-		// Get the server ID from service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
+		// Resolve the server argument
+		server, err := client.ResolveServer(cmd.Context(), args[0])
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
 		// Get the iKVM access link
-		solLink, err := getIkvmResponse(serverID)
+		solLink, err := client.IKVM(cmd.Context(), server.ID)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error getting iKVM access:", err)
-			return
+			return fmt.Errorf("failed to get iKVM access: %w", err)
 		}
 
 		// Print the SOL access link
-		fmt.Printf("%s %s\n", BlueHeading("iKVM Access Link:"), WhiteText(solLink))
+		if isTableOutput() {
+			fmt.Printf("%s %s\n", BlueHeading("iKVM Access Link:"), WhiteText(solLink))
+		} else {
+			if err := printStructured(linkResult{ServiceID: server.ServiceID, Type: "ikvm", Link: solLink}); err != nil {
+				fmt.Fprintln(os.Stderr, "Error writing output:", err)
+			}
+		}
 
 		// If --open flag is specified, try to open the link in a browser
 		openInBrowser, _ := cmd.Flags().GetBool("browser")
 		if !openInBrowser {
-			fmt.Println("Opening iKVM link in your default browser...")
+			fmt.Fprintln(os.Stderr, "Opening iKVM link in your default browser...")
 			err := openBrowser(solLink)
 			if err != nil {
 				fmt.Fprintln(os.Stderr, "Error opening browser:", err)
 			}
 		}
+		return nil
 	},
 }
 
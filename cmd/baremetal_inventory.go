@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// baremetalInventoryCmd represents the baremetal inventory command
+var baremetalInventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Rank and watch the stock of Bare Metal server types",
+}
+
+func init() {
+	baremetalCmd.AddCommand(baremetalInventoryCmd)
+}
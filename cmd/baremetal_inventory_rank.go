@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+)
+
+// rankMetric is a resource servers are ranked on by price per unit
+type rankMetric struct {
+	name   string                                     // Name used in --weights
+	amount func(item icsapi.InventoryDetails) float64 // Units of the resource in a server
+}
+
+// rankMetrics are the resources servers are ranked on
+var rankMetrics = []rankMetric{
+	{"cores", func(item icsapi.InventoryDetails) float64 { return float64(totalCores(item)) }},
+	{"ram", func(item icsapi.InventoryDetails) float64 { return float64(item.TotalRAMGB) }},
+	{"nvme", func(item icsapi.InventoryDetails) float64 { return float64(item.TotalNVMESizeGB) / 1000 }},
+	{"storage", func(item icsapi.InventoryDetails) float64 { return float64(totalStorage(item)) / 1000 }},
+}
+
+// rankWorkloads are the weights of each metric for common workloads
+var rankWorkloads = map[string]map[string]float64{
+	"balanced": {"cores": 1, "ram": 1, "nvme": 1},
+	"compute":  {"cores": 3, "ram": 1},
+	"memory":   {"cores": 1, "ram": 3},
+	"storage":  {"cores": 1, "nvme": 1, "storage": 3},
+}
+
+// inventoryRank is the price/performance of a server type in a location
+type inventoryRank struct {
+	Rank              int      `json:"rank"`                 // Position in the ranking, from 1
+	LocationCode      string   `json:"location_code"`        // Location code
+	SkuProductName    string   `json:"sku_product_name"`     // Server type
+	Price             string   `json:"price"`                // Monthly price
+	Currency          string   `json:"currency"`             // Currency of the prices
+	Quantity          int      `json:"quantity"`             // Quantity available
+	PricePerCore      *float64 `json:"price_per_core"`       // Monthly price per CPU core
+	PricePerGBRAM     *float64 `json:"price_per_gb_ram"`     // Monthly price per GB of RAM
+	PricePerTBNVMe    *float64 `json:"price_per_tb_nvme"`    // Monthly price per TB of NVMe, null without NVMe
+	PricePerTBStorage *float64 `json:"price_per_tb_storage"` // Monthly price per TB of storage of any type
+	Score             float64  `json:"score"`                // Weighted value score, 100 being the best on every metric
+}
+
+// bmInventoryRankCmd represents the inventory rank command
+var bmInventoryRankCmd = &cobra.Command{
+	Use:   "rank",
+	Short: "Rank the inventory by price/performance",
+	Long: `Rank the server types in every datacenter by value for money.
+
+The monthly price per CPU core, per GB of RAM, per TB of NVMe and per TB of storage of
+every type is computed for each server type and location. Each ratio is scored against
+the best one available, and the scores are combined with the weights of a --workload
+(balanced, compute, memory or storage), adjusted with --weights. A score of 100 means
+the best price on every weighted metric.
+
+Only server types in stock are ranked unless --include-sold-out is set. The filters of
+'baremetal deploy list-inventory' narrow the candidates, e.g. to servers with enough
+RAM for a workload.`,
+	Example: `  # Best value for a memory heavy workload
+  ics-cli baremetal inventory rank --workload memory --top 5
+
+  # Weigh cores twice as much as NVMe, among servers with RAID
+  ics-cli baremetal inventory rank --weights cores=2,ram=0,nvme=1 --raid`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, err := inventoryFilterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		workload, _ := cmd.Flags().GetString("workload")
+		overrides, _ := cmd.Flags().GetStringToString("weights")
+		top, _ := cmd.Flags().GetInt("top")
+		includeSoldOut, _ := cmd.Flags().GetBool("include-sold-out")
+
+		weights, err := rankWeights(workload, overrides)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+
+		inventory, err := client.Inventory(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		var candidates []icsapi.InventoryDetails
+		for _, group := range groupInventory(filterInventory(inventory, filter)) {
+			if group.TotalQuantity > 0 || includeSoldOut {
+				candidates = append(candidates, group.InventoryDetails)
+			}
+		}
+
+		ranks := rankInventory(candidates, weights)
+		if top > 0 && len(ranks) > top {
+			ranks = ranks[:top]
+		}
+
+		if !isTableOutput() {
+			return printStructured(ranks)
+		}
+
+		if len(ranks) == 0 {
+			fmt.Println("No inventory available.")
+			return nil
+		}
+
+		headerFmt := color.New(color.FgBlue, color.Bold).SprintfFunc()
+		columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+		tbl := table.New("Rank", "Location", "Server Type", "Price", "Per Core", "Per GB RAM", "Per TB NVMe", "Per TB Storage", "Score", "Available Quantity")
+		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+		for _, rank := range ranks {
+			tbl.AddRow(
+				rank.Rank,
+				rank.LocationCode,
+				rank.SkuProductName,
+				strings.TrimSpace(rank.Price+" "+rank.Currency),
+				formatRatio(rank.PricePerCore),
+				formatRatio(rank.PricePerGBRAM),
+				formatRatio(rank.PricePerTBNVMe),
+				formatRatio(rank.PricePerTBStorage),
+				fmt.Sprintf("%.1f", rank.Score),
+				strconv.Itoa(rank.Quantity),
+			)
+		}
+
+		tbl.Print()
+		return nil
+	},
+}
+
+func init() {
+	baremetalInventoryCmd.AddCommand(bmInventoryRankCmd)
+
+	addInventoryFilterFlags(bmInventoryRankCmd)
+	bmInventoryRankCmd.Flags().String("workload", "balanced", "Workload setting the weights: balanced, compute, memory or storage")
+	bmInventoryRankCmd.Flags().StringToString("weights", nil, "Weights of cores, ram, nvme and storage, overriding the workload (e.g., cores=2,nvme=0)")
+	bmInventoryRankCmd.Flags().Int("top", 0, "Show only the best N server types")
+	bmInventoryRankCmd.Flags().Bool("include-sold-out", false, "Rank server types that are out of stock too")
+}
+
+// rankWeights returns the weights of a workload with overrides from --weights
+func rankWeights(workload string, overrides map[string]string) (map[string]float64, error) {
+	preset, ok := rankWorkloads[workload]
+	if !ok {
+		return nil, usageError("invalid --workload %q. Must be one of: %s", workload, strings.Join(slices.Sorted(maps.Keys(rankWorkloads)), ", "))
+	}
+	weights := maps.Clone(preset)
+
+	for name, value := range overrides {
+		if !slices.ContainsFunc(rankMetrics, func(m rankMetric) bool { return m.name == name }) {
+			return nil, usageError("invalid weight %q. Must be one of: cores, ram, nvme, storage", name)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 0 {
+			return nil, usageError("invalid weight for %s: %q must be a number of at least 0", name, value)
+		}
+		weights[name] = weight
+	}
+
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	if total == 0 {
+		return nil, usageError("at least one weight must be above 0")
+	}
+
+	return weights, nil
+}
+
+// rankInventory ranks inventory by weighted price/performance, best first. Each metric scores
+// the best price per unit among the items as 1 and others in proportion, or 0 for an item
+// without the resource. Items without a valid price are left out.
+func rankInventory(inventory []icsapi.InventoryDetails, weights map[string]float64) []inventoryRank {
+	// Price per unit of each metric, 0 when the item has none of the resource
+	ratios := make([][]float64, 0, len(inventory))
+	var items []icsapi.InventoryDetails
+	for _, item := range inventory {
+		price := inventoryPrice(item)
+		if price <= 0 {
+			continue
+		}
+
+		itemRatios := make([]float64, len(rankMetrics))
+		for i, metric := range rankMetrics {
+			if amount := metric.amount(item); amount > 0 {
+				itemRatios[i] = price / amount
+			}
+		}
+		ratios = append(ratios, itemRatios)
+		items = append(items, item)
+	}
+
+	// Find the best price per unit of each metric
+	best := make([]float64, len(rankMetrics))
+	for _, itemRatios := range ratios {
+		for i, ratio := range itemRatios {
+			if ratio > 0 && (best[i] == 0 || ratio < best[i]) {
+				best[i] = ratio
+			}
+		}
+	}
+
+	totalWeight := 0.0
+	for _, weight := range weights {
+		totalWeight += weight
+	}
+
+	ranks := make([]inventoryRank, len(items))
+	for n, item := range items {
+		rank := inventoryRank{
+			LocationCode:   item.LocationCode,
+			SkuProductName: item.SkuProductName,
+			Price:          item.Price,
+			Currency:       item.CurrencyCode,
+			Quantity:       item.Quantity,
+		}
+
+		score := 0.0
+		for i, metric := range rankMetrics {
+			ratio := ratios[n][i]
+			if ratio > 0 {
+				score += weights[metric.name] * best[i] / ratio
+			}
+		}
+		rank.Score = 100 * score / totalWeight
+
+		ratioOf := func(i int) *float64 {
+			if ratios[n][i] == 0 {
+				return nil
+			}
+			return &ratios[n][i]
+		}
+		rank.PricePerCore, rank.PricePerGBRAM, rank.PricePerTBNVMe, rank.PricePerTBStorage = ratioOf(0), ratioOf(1), ratioOf(2), ratioOf(3)
+
+		ranks[n] = rank
+	}
+
+	// Best score first, then cheapest
+	slices.SortStableFunc(ranks, func(a, b inventoryRank) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(inventoryPrice(icsapi.InventoryDetails{Price: a.Price}), inventoryPrice(icsapi.InventoryDetails{Price: b.Price})))
+	})
+	for i := range ranks {
+		ranks[i].Rank = i + 1
+	}
+
+	return ranks
+}
+
+// formatRatio formats a price per unit, or "-" if there is none
+func formatRatio(ratio *float64) string {
+	if ratio == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *ratio)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+)
+
+// rankTestInventory has a compute, a memory and a storage heavy server, and one without a price
+var rankTestInventory = []icsapi.InventoryDetails{
+	{SkuProductName: "cpu", LocationCode: "NYC1", Price: "100.00", CPUCores: 16, CPUCount: 2, TotalRAMGB: 64},
+	{SkuProductName: "mem", LocationCode: "NYC1", Price: "100.00", CPUCores: 8, TotalRAMGB: 512},
+	{SkuProductName: "nvme", LocationCode: "AMS1", Price: "200.00", CPUCores: 16, TotalRAMGB: 128, TotalNVMESizeGB: 4000},
+	{SkuProductName: "unpriced", LocationCode: "AMS1", Price: "", CPUCores: 64, TotalRAMGB: 1024},
+}
+
+func TestRankInventory(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload string
+		want     []string
+	}{
+		{name: "compute", workload: "compute", want: []string{"cpu", "mem", "nvme"}},
+		{name: "memory", workload: "memory", want: []string{"mem", "cpu", "nvme"}},
+		{name: "storage", workload: "storage", want: []string{"nvme", "cpu", "mem"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights, err := rankWeights(tt.workload, nil)
+			if err != nil {
+				t.Fatalf("rankWeights(%q) error = %v", tt.workload, err)
+			}
+
+			ranks := rankInventory(rankTestInventory, weights)
+			var got []string
+			for i, rank := range ranks {
+				got = append(got, rank.SkuProductName)
+				if rank.Rank != i+1 {
+					t.Errorf("%s has rank %d, want %d", rank.SkuProductName, rank.Rank, i+1)
+				}
+				if rank.Score <= 0 || rank.Score > 100 {
+					t.Errorf("%s has score %v, want above 0 and at most 100", rank.SkuProductName, rank.Score)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("rankInventory() order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankInventoryTies(t *testing.T) {
+	// Same price per core, so the cheaper server comes first
+	inventory := []icsapi.InventoryDetails{
+		{SkuProductName: "large", Price: "200", CPUCores: 20},
+		{SkuProductName: "small", Price: "100", CPUCores: 10},
+	}
+	ranks := rankInventory(inventory, map[string]float64{"cores": 1})
+	if len(ranks) != 2 || ranks[0].SkuProductName != "small" || ranks[0].Score != 100 || ranks[1].Score != 100 {
+		t.Errorf("rankInventory() = %+v, want small then large, both scoring 100", ranks)
+	}
+	if ranks[0].PricePerTBNVMe != nil {
+		t.Errorf("price per TB of NVMe = %v, want nil without NVMe", *ranks[0].PricePerTBNVMe)
+	}
+}
+
+func TestRankWeights(t *testing.T) {
+	tests := []struct {
+		name      string
+		workload  string
+		overrides map[string]string
+		want      map[string]float64
+		wantErr   bool
+	}{
+		{name: "workload", workload: "compute", want: map[string]float64{"cores": 3, "ram": 1}},
+		{name: "override", workload: "balanced", overrides: map[string]string{"nvme": "0", "storage": "2.5"}, want: map[string]float64{"cores": 1, "ram": 1, "nvme": 0, "storage": 2.5}},
+		{name: "unknown workload", workload: "gaming", wantErr: true},
+		{name: "unknown metric", workload: "balanced", overrides: map[string]string{"gpu": "1"}, wantErr: true},
+		{name: "negative weight", workload: "balanced", overrides: map[string]string{"ram": "-1"}, wantErr: true},
+		{name: "not a number", workload: "balanced", overrides: map[string]string{"ram": "lots"}, wantErr: true},
+		{name: "all zero", workload: "compute", overrides: map[string]string{"cores": "0", "ram": "0"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rankWeights(tt.workload, tt.overrides)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("rankWeights() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("rankWeights() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("rankWeights() = %v, want %v", got, tt.want)
+			}
+			for name, weight := range tt.want {
+				if got[name] != weight {
+					t.Errorf("weight of %s = %v, want %v", name, got[name], weight)
+				}
+			}
+		})
+	}
+
+	// Overrides must not change the presets
+	if rankWorkloads["balanced"]["nvme"] != 1 {
+		t.Errorf("rankWeights() modified the balanced workload: %v", rankWorkloads["balanced"])
+	}
+}
@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultWatchInterval is the default time between inventory checks
+const defaultWatchInterval = time.Minute
+
+// watchEnvPrefix is the prefix of the environment variables describing an event to an
+// --exec hook. No config key may start with "watch_", so they never set a setting.
+const watchEnvPrefix = envPrefix + "_WATCH_"
+
+// stockEvent is reported when stock appears, and when an order is placed for it
+type stockEvent struct {
+	Event      string    `json:"event"`                 // "stock_available" or "order_placed"
+	Time       time.Time `json:"time"`                  // When the event happened
+	SKU        string    `json:"sku"`                   // Server type
+	Datacenter string    `json:"datacenter"`            // Location code
+	Quantity   int       `json:"quantity"`              // Quantity available
+	Price      string    `json:"price"`                 // Monthly price of one server
+	Currency   string    `json:"currency"`              // Currency of the price
+	ServiceIDs []int     `json:"service_ids,omitempty"` // Service IDs of the servers ordered, for "order_placed"
+}
+
+// bmInventoryWatchCmd represents the inventory watch command
+var bmInventoryWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch the inventory and alert or order when stock appears",
+	Long: `Check the inventory of a server type every --interval and report when at least
+--min-qty servers are available. The event is printed, and can also be posted as JSON to
+a --webhook URL or passed to an --exec command, which receives the JSON on stdin and the
+ICS_WATCH_EVENT, ICS_WATCH_SKU, ICS_WATCH_DATACENTER, ICS_WATCH_QUANTITY and ICS_WATCH_PRICE
+environment variables.
+
+An event fires when stock appears, not on every check while it stays available. Without
+--datacenter every datacenter offering the server type is watched.
+
+With --order-file the order in the file is checked up front, then placed without prompting
+as soon as enough servers are available, and the command exits. --max-spend caps the
+monthly price of the order and is required with --order-file. The order file holds an
+order like the one printed by 'baremetal deploy create --dry-run -o json'.`,
+	Example: `  # Wait until two c2i.large are available in NYC1, then exit
+  ics-cli baremetal inventory watch --sku c2i.large --datacenter NYC1 --min-qty 2 --once
+
+  # Post to a local webhook whenever c1i.small stock appears anywhere
+  ics-cli baremetal inventory watch --sku c1i.small --webhook http://localhost:9000/stock
+
+  # Order as soon as stock appears, spending at most 1500 a month
+  ics-cli baremetal deploy create --sku c2i.large --datacenter NYC1 --os DEBIAN_12 --quantity 2 --dry-run -o json > order.json
+  ics-cli baremetal inventory watch --order-file order.json --max-spend 1500`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sku, _ := cmd.Flags().GetString("sku")
+		datacenter, _ := cmd.Flags().GetString("datacenter")
+		minQty, _ := cmd.Flags().GetInt("min-qty")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		once, _ := cmd.Flags().GetBool("once")
+		webhook, _ := cmd.Flags().GetString("webhook")
+		execHook, _ := cmd.Flags().GetString("exec")
+		orderFile, _ := cmd.Flags().GetString("order-file")
+		maxSpend, _ := cmd.Flags().GetFloat64("max-spend")
+
+		if interval <= 0 {
+			return usageError("--interval must be positive")
+		}
+		if minQty < 1 {
+			return usageError("--min-qty must be at least 1")
+		}
+
+		// The order file sets the server type and datacenter to watch
+		var order *icsapi.OrderRequest
+		if orderFile != "" {
+			var err error
+			order, err = loadOrderFile(orderFile)
+			if err != nil {
+				return err
+			}
+			if maxSpend <= 0 {
+				return usageError("--max-spend is required with --order-file")
+			}
+			if sku != "" && !strings.EqualFold(sku, order.SKUProductName) || datacenter != "" && !strings.EqualFold(datacenter, order.LocationCode) {
+				return usageError("--sku and --datacenter must match the order file")
+			}
+			sku, datacenter = order.SKUProductName, order.LocationCode
+			minQty = max(minQty, order.Quantity)
+		}
+
+		if sku == "" {
+			return usageError("--sku or --order-file is required")
+		}
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+
+		// Check the order now, rather than when the stock appears. Stock is not checked yet.
+		if order != nil {
+			if err := checkWatchedOrder(cmd.Context(), client, *order, maxSpend, false); err != nil {
+				return err
+			}
+		}
+
+		where := "any datacenter"
+		if datacenter != "" {
+			where = datacenter
+		}
+		fmt.Fprintf(os.Stderr, "Watching for %d or more %s in %s, checking every %s...\n", minQty, sku, where, interval)
+
+		watch := newStockWatch(sku, datacenter, minQty)
+		hooks := stockHooks{webhook: webhook, exec: execHook}
+
+		for {
+			inventory, err := client.Inventory(cmd.Context())
+			switch {
+			case cmd.Context().Err() != nil:
+				return nil
+			case errors.Is(err, icsapi.ErrUnauthorized):
+				return err
+			}
+
+			// Keep watching through transient failures, without taking the stock as gone
+			var appeared, inStock []GroupedInventory
+			if err != nil {
+				fmt.Fprintln(os.Stderr, YellowText(fmt.Sprintf("Warning: %v", err)))
+			} else {
+				appeared, inStock = watch.update(inventory)
+			}
+
+			for _, item := range appeared {
+				if err := reportStockEvent(cmd.Context(), newStockEvent(item), hooks); err != nil {
+					return err
+				}
+			}
+
+			// Order while the stock lasts. The pre-flight sums the same inventory rows the
+			// watch does, so it only fails if the stock is gone again or the order changed.
+			if order != nil && len(inStock) > 0 {
+				serviceIDs, err := placeWatchedOrder(cmd.Context(), client, *order, maxSpend)
+				var preflight *preflightFailure
+				switch {
+				case errors.As(err, &preflight):
+					fmt.Fprintln(os.Stderr, YellowText(fmt.Sprintf("Warning: not ordering yet: %v", err)))
+				case err != nil:
+					return err
+				default:
+					event := newStockEvent(inStock[0])
+					event.Event = "order_placed"
+					event.ServiceIDs = serviceIDs
+					return reportStockEvent(cmd.Context(), event, hooks)
+				}
+			}
+
+			// With an order file, the watch goes on until the order is placed
+			if once && order == nil && len(appeared) > 0 {
+				return nil
+			}
+
+			select {
+			case <-cmd.Context().Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+func init() {
+	baremetalInventoryCmd.AddCommand(bmInventoryWatchCmd)
+
+	bmInventoryWatchCmd.Flags().String("sku", "", "Server type to watch (e.g., c1i.small)")
+	bmInventoryWatchCmd.Flags().String("datacenter", "", "Datacenter to watch (e.g., NYC1), all if not set")
+	bmInventoryWatchCmd.Flags().Int("min-qty", 1, "Minimum quantity available to report")
+	bmInventoryWatchCmd.Flags().Duration("interval", defaultWatchInterval, "Time between inventory checks")
+	bmInventoryWatchCmd.Flags().Bool("once", false, "Exit after the first event")
+	bmInventoryWatchCmd.Flags().String("webhook", "", "URL to post events to as JSON")
+	bmInventoryWatchCmd.Flags().String("exec", "", "Command to run for each event, with the event as JSON on stdin")
+	bmInventoryWatchCmd.Flags().String("order-file", "", "Order to place as soon as stock appears (YAML or JSON)")
+	bmInventoryWatchCmd.Flags().Float64("max-spend", 0, "Maximum monthly price of the order, required with --order-file")
+}
+
+// stockWatch tracks which locations have enough stock of the watched server type
+type stockWatch struct {
+	filter    inventoryFilter // Server type and datacenter watched
+	minQty    int             // Quantity that counts as in stock
+	available map[string]bool // Locations that had enough stock at the last check
+}
+
+// newStockWatch returns a watch of a server type, in a datacenter or in all of them
+func newStockWatch(sku, datacenter string, minQty int) *stockWatch {
+	return &stockWatch{filter: inventoryFilter{datacenter: datacenter, sku: sku}, minQty: minQty, available: make(map[string]bool)}
+}
+
+// update records an inventory check. It returns the locations where the stock appeared
+// since the last check, and every location with enough stock. A location missing from
+// the inventory has no stock, so an event fires again when it comes back.
+func (w *stockWatch) update(inventory []icsapi.InventoryDetails) (appeared, inStock []GroupedInventory) {
+	available := make(map[string]bool)
+	for _, item := range groupInventory(filterInventory(inventory, w.filter)) {
+		if item.TotalQuantity < w.minQty {
+			continue
+		}
+		available[item.LocationCode] = true
+		inStock = append(inStock, item)
+		if !w.available[item.LocationCode] {
+			appeared = append(appeared, item)
+		}
+	}
+
+	w.available = available
+	return appeared, inStock
+}
+
+// newStockEvent returns the event of stock appearing for an inventory group
+func newStockEvent(item GroupedInventory) stockEvent {
+	return stockEvent{
+		Event:      "stock_available",
+		Time:       time.Now(),
+		SKU:        item.SkuProductName,
+		Datacenter: item.LocationCode,
+		Quantity:   item.TotalQuantity,
+		Price:      item.Price,
+		Currency:   item.CurrencyCode,
+	}
+}
+
+// preflightFailure is returned when a watched order does not pass its pre-flight checks
+type preflightFailure struct {
+	problems []string
+}
+
+func (e *preflightFailure) Error() string {
+	return strings.Join(e.problems, "; ")
+}
+
+// loadOrderFile reads an order from a YAML or JSON file. The file holds an order, or the
+// result of 'create --dry-run' with the order under "order".
+func loadOrderFile(path string) (*icsapi.OrderRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading order file: %w", err)
+	}
+
+	// Decode YAML (a superset of JSON) generically, then reuse the JSON tags
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, usageError("error parsing order file: %v", err)
+	}
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, usageError("error parsing order file: %v", err)
+	}
+
+	var file struct {
+		icsapi.OrderRequest
+		Order *icsapi.OrderRequest `json:"order"`
+	}
+	if err := json.Unmarshal(jsonData, &file); err != nil {
+		return nil, usageError("error parsing order file: %v", err)
+	}
+
+	order := file.OrderRequest
+	if file.Order != nil {
+		order = *file.Order
+	}
+
+	if order.SKUProductName == "" || order.LocationCode == "" || order.OperatingSystemProductCode == "" {
+		return nil, usageError("the order file must set sku_product_name, location_code and operating_system_product_code")
+	}
+	if order.Quantity <= 0 {
+		order.Quantity = 1
+	}
+
+	return &order, nil
+}
+
+// checkWatchedOrder runs the pre-flight checks of an order and checks its monthly price
+// against the spend cap. Stock is only checked if checkStock is set.
+func checkWatchedOrder(ctx context.Context, client *icsapi.Client, order icsapi.OrderRequest, maxSpend float64, checkStock bool) error {
+	catalog, err := client.GetOrderCatalog(ctx, order)
+	if err != nil {
+		return fmt.Errorf("failed to run pre-flight checks: %w", err)
+	}
+
+	// Validating a zero quantity skips the stock check
+	check := order
+	if !checkStock {
+		check.Quantity = 0
+	}
+	if problems := catalog.Validate(check); len(problems) > 0 {
+		if checkStock {
+			return &preflightFailure{problems: problems}
+		}
+		return preflightError(problems)
+	}
+
+	quote, err := catalog.Quote(order)
+	if err != nil {
+		return rejectedError("failed to price the order: %w", err)
+	}
+	if quote.Total > maxSpend {
+		return rejectedError("the order costs %.2f %s a month, more than the --max-spend of %.2f", quote.Total, quote.Currency, maxSpend)
+	}
+
+	return nil
+}
+
+// placeWatchedOrder checks an order again, with its stock and current price, and places it
+func placeWatchedOrder(ctx context.Context, client *icsapi.Client, order icsapi.OrderRequest, maxSpend float64) ([]int, error) {
+	if err := checkWatchedOrder(ctx, client, order, maxSpend, true); err != nil {
+		return nil, err
+	}
+
+	return client.PlaceOrder(ctx, order)
+}
+
+// stockHooks are the destinations of stock events besides stdout
+type stockHooks struct {
+	webhook string // URL to post events to
+	exec    string // Command to run for each event
+}
+
+// reportStockEvent prints an event and sends it to the hooks. Hook failures are warnings,
+// so a broken hook does not stop the watch.
+func reportStockEvent(ctx context.Context, event stockEvent, hooks stockHooks) error {
+	if !isTableOutput() {
+		if err := printStructured(event); err != nil {
+			return err
+		}
+	} else {
+		message := fmt.Sprintf("%s in stock in %s: %d available at %s %s", event.SKU, event.Datacenter, event.Quantity, event.Price, event.Currency)
+		if event.Event == "order_placed" {
+			message = fmt.Sprintf("Order placed for %s in %s. Service IDs: %v", event.SKU, event.Datacenter, event.ServiceIDs)
+		}
+		fmt.Printf("%s %s\n", WhiteText(event.Time.Format(time.DateTime)), GreenText(message))
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if hooks.webhook != "" {
+		if err := postStockEvent(ctx, hooks.webhook, payload); err != nil {
+			fmt.Fprintln(os.Stderr, YellowText(fmt.Sprintf("Warning: webhook failed: %v", err)))
+		}
+	}
+
+	if hooks.exec != "" {
+		if err := execStockEvent(ctx, hooks.exec, event, payload); err != nil {
+			fmt.Fprintln(os.Stderr, YellowText(fmt.Sprintf("Warning: exec hook failed: %v", err)))
+		}
+	}
+
+	return nil
+}
+
+// postStockEvent posts an event to a webhook
+func postStockEvent(ctx context.Context, url string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// execStockEvent runs a command for an event through the shell
+func execStockEvent(ctx context.Context, command string, event stockEvent, payload []byte) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	c.Stdin = bytes.NewReader(payload)
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(),
+		watchEnvPrefix+"EVENT="+event.Event,
+		watchEnvPrefix+"SKU="+event.SKU,
+		watchEnvPrefix+"DATACENTER="+event.Datacenter,
+		watchEnvPrefix+"QUANTITY="+strconv.Itoa(event.Quantity),
+		watchEnvPrefix+"PRICE="+event.Price,
+	)
+
+	return c.Run()
+}
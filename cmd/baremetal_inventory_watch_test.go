@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+)
+
+func TestStockWatch(t *testing.T) {
+	row := func(location string, quantity int) icsapi.InventoryDetails {
+		return icsapi.InventoryDetails{SkuProductName: "c2i.large", LocationCode: location, Quantity: quantity, Price: "649.00"}
+	}
+	other := icsapi.InventoryDetails{SkuProductName: "c1i.small", LocationCode: "NYC1", Quantity: 10}
+
+	// Each check is the inventory returned by a poll, in order
+	checks := []struct {
+		name      string
+		inventory []icsapi.InventoryDetails
+		appeared  []string
+		inStock   []string
+	}{
+		{name: "not enough", inventory: []icsapi.InventoryDetails{row("NYC1", 1), other}},
+		{name: "split over two rows", inventory: []icsapi.InventoryDetails{row("NYC1", 1), row("NYC1", 1)}, appeared: []string{"NYC1"}, inStock: []string{"NYC1"}},
+		{name: "still in stock", inventory: []icsapi.InventoryDetails{row("NYC1", 3), row("AMS1", 1)}, inStock: []string{"NYC1"}},
+		{name: "gone from the inventory", inventory: []icsapi.InventoryDetails{other}},
+		{name: "back in stock", inventory: []icsapi.InventoryDetails{row("NYC1", 2), row("AMS1", 2)}, appeared: []string{"NYC1", "AMS1"}, inStock: []string{"NYC1", "AMS1"}},
+		{name: "sold out in one location", inventory: []icsapi.InventoryDetails{row("NYC1", 0), row("AMS1", 2)}, inStock: []string{"AMS1"}},
+		{name: "back in the other location", inventory: []icsapi.InventoryDetails{row("NYC1", 2), row("AMS1", 2)}, appeared: []string{"NYC1"}, inStock: []string{"NYC1", "AMS1"}},
+	}
+
+	watch := newStockWatch("c2i.large", "", 2)
+	for _, check := range checks {
+		appeared, inStock := watch.update(check.inventory)
+		if got := stockLocations(appeared); !slices.Equal(got, check.appeared) {
+			t.Errorf("%s: appeared in %v, want %v", check.name, got, check.appeared)
+		}
+		if got := stockLocations(inStock); !slices.Equal(got, check.inStock) {
+			t.Errorf("%s: in stock in %v, want %v", check.name, got, check.inStock)
+		}
+	}
+}
+
+// stockLocations returns the locations of inventory groups
+func stockLocations(items []GroupedInventory) []string {
+	var locations []string
+	for _, item := range items {
+		locations = append(locations, item.LocationCode)
+	}
+	return locations
+}
@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/UK2Group/ics-cli/icsapi"
 	"github.com/fatih/color"
 	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
@@ -15,23 +15,44 @@ var listCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "List all baremetal servers in your account",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
 
 		// Make API call to fetch server list
-		servers, err := getServerList()
+		servers, err := client.ListServers(cmd.Context())
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error fetching server list:", err)
-			return
+			return err
 		}
 
-		// Get flags
+		// Get flags. The site defaults to the site config key
+		siteFromConfig := !cmd.Flags().Changed("site")
+		if err := applyConfigDefault(cmd, "site", "site"); err != nil {
+			return err
+		}
 		displayBySite, _ := cmd.Flags().GetBool("display")
 		filterBySite, _ := cmd.Flags().GetString("site")
+		siteFromConfig = siteFromConfig && filterBySite != ""
+
+		if filterBySite != "" {
+			servers = filterServersBySite(servers, filterBySite, siteFromConfig)
+		}
+
+		if !isTableOutput() {
+			return printStructured(servers)
+		}
 
 		// If no servers returned
 		if len(servers) == 0 {
+			if siteFromConfig {
+				fmt.Printf("No servers found in site %s, set by the site config key. Use --site \"\" to list every site.\n", filterBySite)
+				return nil
+			}
 			fmt.Println("No servers found in your account.")
-			return
+			return nil
 		}
 
 		headerFmt := color.New(color.FgBlue).SprintfFunc()
@@ -39,7 +60,7 @@ var listCmd = &cobra.Command{
 
 		// Create a map to group servers by datacenter if filtering by site
 		if displayBySite {
-			datacenterMap := make(map[string][]Server)
+			datacenterMap := make(map[string][]icsapi.Server)
 			for _, server := range servers {
 				datacenterMap[server.DatacenterName] = append(datacenterMap[server.DatacenterName], server)
 			}
@@ -70,11 +91,6 @@ var listCmd = &cobra.Command{
 
 			// Build each server in a row
 			for _, server := range servers {
-				// Use "contains" logic instead of exact matching
-				if filterBySite != "" && !strings.Contains(strings.ToLower(server.DatacenterName), strings.ToLower(filterBySite)) {
-					continue
-				}
-
 				tbl.AddRow(server.ServiceID,
 					server.Hostname,
 					server.PublicIP,
@@ -85,12 +101,27 @@ var listCmd = &cobra.Command{
 
 			tbl.Print()
 		}
+		return nil
 	},
 }
 
+// filterServersBySite returns the servers in a site. A site given on the command line
+// matches part of the datacenter name, while the default site of the config must match
+// it exactly, as it filters the list without being asked for each time.
+func filterServersBySite(servers []icsapi.Server, site string, exact bool) []icsapi.Server {
+	filtered := make([]icsapi.Server, 0, len(servers))
+	for _, server := range servers {
+		if exact && strings.EqualFold(server.DatacenterName, site) ||
+			!exact && strings.Contains(strings.ToLower(server.DatacenterName), strings.ToLower(site)) {
+			filtered = append(filtered, server)
+		}
+	}
+	return filtered
+}
+
 func init() {
 	baremetalCmd.AddCommand(listCmd)
 
 	listCmd.Flags().BoolP("display", "d", false, "Display servers grouped per site")
-	listCmd.Flags().StringP("site", "s", "", "Filter servers by site")
+	listCmd.Flags().StringP("site", "s", "", "Filter servers by site (default is the site config key, --site \"\" lists every site)")
 }
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+)
+
+func TestFilterServersBySite(t *testing.T) {
+	servers := []icsapi.Server{
+		{ServiceID: 1, DatacenterName: "NYC1"},
+		{ServiceID: 2, DatacenterName: "NYC10"},
+		{ServiceID: 3, DatacenterName: "AMS1"},
+	}
+
+	tests := []struct {
+		name  string
+		site  string
+		exact bool
+		want  []int
+	}{
+		{name: "flag matches part of the name", site: "nyc", want: []int{1, 2}},
+		{name: "flag with a full name", site: "NYC1", want: []int{1, 2}},
+		{name: "config matches exactly", site: "NYC1", exact: true, want: []int{1}},
+		{name: "config ignores case", site: "ams1", exact: true, want: []int{3}},
+		{name: "config with part of a name", site: "NYC", exact: true, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, server := range filterServersBySite(servers, tt.site, tt.exact) {
+				got = append(got, server.ServiceID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("filterServersBySite(%q, %t) = %v, want %v", tt.site, tt.exact, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"strconv"
 
 	"github.com/fatih/color"
 	"github.com/rodaine/table"
@@ -12,41 +10,39 @@ import (
 
 // bmNameCmd represents the name command
 var bmOSList = &cobra.Command{
-	Use:   "oslist [serviceID]",
+	Use:   "oslist [server]",
 	Short: "Get a list of operating systems available for a server",
 	Example: `  
 	# Get a list of operating systems available for a server with service ID 123456
 	ics-cli baremetal oslist 123456`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		// Get service ID from arguments
-		serviceID := args[0]
-
-		// Validate service ID is a number
-		if _, err := strconv.Atoi(serviceID); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be a number")
-			return
+		client, err := newAPIClient()
+		if err != nil {
+			return err
 		}
 
-		// Step 1: Get the server ID from service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
+		// Resolve the server argument
+		server, err := client.ResolveServer(cmd.Context(), args[0])
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
 		// Get the OS List
-		osList, err := getOSList(serverID)
+		osList, err := client.ListOS(cmd.Context(), server.ID)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error getting OS List:", err)
-			return
+			return err
+		}
+
+		if !isTableOutput() {
+			return printStructured(osList)
 		}
 
 		// If no ssh keys returned
 		if len(osList) == 0 {
 			fmt.Println("No available Operating Systems found.")
-			return
+			return nil
 		}
 
 		headerFmt := color.New(color.FgBlue).SprintfFunc()
@@ -68,6 +64,7 @@ var bmOSList = &cobra.Command{
 		}
 
 		tbl.Print()
+		return nil
 	},
 }
 
@@ -1,60 +1,29 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-	"strconv"
-
+	"github.com/UK2Group/ics-cli/icsapi"
 	"github.com/spf13/cobra"
 )
 
 // poweroffCmd represents the poweroff command
 var poweroffCmd = &cobra.Command{
-	Use:   "poweroff [serviceID]",
-	Short: "Power off a baremetal server",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-
-		// Get service ID from arguments
-		serviceID := args[0]
-
-		// Validate service ID is a number
-		if _, err := strconv.Atoi(serviceID); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be a number")
-			return
-		}
-
-		// Step 1: Get the server ID from service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
-		}
-
-		// Check if user wants to proceed
-		dontPrompt, _ := cmd.Flags().GetBool("dont")
-		if !dontPrompt {
-			fmt.Print("Are you sure you want to power off the server? (y/n): ")
-			var response string
-			fmt.Scanln(&response)
-			if response != "y" {
-				return
-			}
-		}
-
-		// Power off the server
-		powerOff, err := setPowerOff(serverID)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error sending power command:", err)
-			return
-		}
-
-		if powerOff {
-			fmt.Printf("%s\n", BlueHeading("Successfully powered off the server."))
-		} else {
-			fmt.Printf("%s\n", RedText("Failed to power off the server. Please try again."))
-		}
-
+	Use:   "poweroff [server...]",
+	Short: "Power off one or more baremetal servers",
+	Example: `  # Power off a server
+  ics-cli baremetal poweroff web-01
+
+  # Power off every web server in NYC1, 10 at a time, without prompting
+  ics-cli baremetal poweroff --match 'web-*' --site NYC1 --parallel 10 -d`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPowerAction(cmd, args, powerAction{
+			name:    "poweroff",
+			command: "power",
+			prompt:  "power off %s",
+			success: "Successfully powered off the server.",
+			failure: "Failed to power off the server. Please try again.",
+			run:     (*icsapi.Client).PowerOff,
+			waitFor: false,
+		})
 	},
 }
 
@@ -62,4 +31,6 @@ func init() {
 	baremetalCmd.AddCommand(poweroffCmd)
 
 	poweroffCmd.Flags().BoolP("dont", "d", false, "Don't prompt for confirmation")
+	addServerSelectionFlags(poweroffCmd)
+	addWaitFlags(poweroffCmd)
 }
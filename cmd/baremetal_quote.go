@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// bmQuoteCmd represents the quote command
+var bmQuoteCmd = &cobra.Command{
+	Use:   "quote",
+	Short: "Estimate the monthly price of a Baremetal Server Order",
+	Long: `Estimate the monthly price of a Baremetal Server order without placing it.
+
+The price of the server, operating system, license and support level is taken from the
+inventory and add-ons of the datacenter. Operating systems licensed per core are priced
+for every core of the server. The breakdown is per server, followed by the total for the
+quantity ordered. Additional bandwidth is billed separately and not included.`,
+	Example: `  # Price three servers with Windows and premium support
+  ics-cli baremetal quote --sku c1i.small --datacenter NYC1 --os WIN_2022_STD --support PREMSUP --quantity 3`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		orderRequest, err := orderFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+
+		catalog, err := client.GetOrderCatalog(cmd.Context(), orderRequest)
+		if err != nil {
+			return fmt.Errorf("failed to get pricing: %w", err)
+		}
+
+		// Stock is not checked, a quote is still useful when the SKU is sold out
+		quote, err := catalog.Quote(orderRequest)
+		if err != nil {
+			return rejectedError("failed to price the order: %w", err)
+		}
+
+		if !isTableOutput() {
+			return printStructured(quote)
+		}
+
+		printQuote(os.Stdout, orderRequest, quote)
+		return nil
+	},
+}
+
+func init() {
+	baremetalCmd.AddCommand(bmQuoteCmd)
+
+	addOrderFlags(bmQuoteCmd)
+}
@@ -1,60 +1,30 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-	"strconv"
-
+	"github.com/UK2Group/ics-cli/icsapi"
 	"github.com/spf13/cobra"
 )
 
 // rebootCmd represents the reboot command
 var rebootCmd = &cobra.Command{
-	Use:   "reboot [serviceID]",
-	Short: "Reboot a baremetal server",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-
-		// Get service ID from arguments
-		serviceID := args[0]
-
-		// Validate service ID is a number
-		if _, err := strconv.Atoi(serviceID); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be a number")
-			return
-		}
-
-		// Step 1: Get the server ID from service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
-		}
-
-		// Check if user wants to proceed
-		dontPrompt, _ := cmd.Flags().GetBool("dont")
-		if !dontPrompt {
-			fmt.Print("Are you sure you want to reboot the server? (y/n): ")
-			var response string
-			fmt.Scanln(&response)
-			if response != "y" {
-				return
-			}
-		}
-
-		// Reboot the server
-		rebootServer, err := setReboot(serverID)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error sending power command:", err)
-			return
-		}
-
-		if rebootServer {
-			fmt.Printf("%s\n", BlueHeading("Successfully rebooted the server."))
-		} else {
-			fmt.Printf("%s\n", RedText("Failed to reboot the server. Please try again."))
-		}
-
+	Use:   "reboot [server...]",
+	Short: "Reboot one or more baremetal servers",
+	Example: `  # Reboot a server
+  ics-cli baremetal reboot web-01
+
+  # Reboot every web server in NYC1, 10 at a time, without prompting
+  ics-cli baremetal reboot --match 'web-*' --site NYC1 --parallel 10 -d`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPowerAction(cmd, args, powerAction{
+			name:    "reboot",
+			command: "power",
+			prompt:  "reboot %s",
+			success: "Successfully rebooted the server.",
+			failure: "Failed to reboot the server. Please try again.",
+			run:     (*icsapi.Client).Reboot,
+			waitFor: true,
+			cycle:   true,
+		})
 	},
 }
 
@@ -62,4 +32,6 @@ func init() {
 	baremetalCmd.AddCommand(rebootCmd)
 
 	rebootCmd.Flags().BoolP("dont", "d", false, "Don't prompt for confirmation")
+	addServerSelectionFlags(rebootCmd)
+	addWaitFlags(rebootCmd)
 }
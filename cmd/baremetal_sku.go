@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// baremetalSkuCmd represents the baremetal sku command
+var baremetalSkuCmd = &cobra.Command{
+	Use:   "sku",
+	Short: "Inspect Bare Metal server types",
+}
+
+func init() {
+	baremetalCmd.AddCommand(baremetalSkuCmd)
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+)
+
+// skuDescription is the hardware, metadata and availability of a server type
+type skuDescription struct {
+	SKU              string            `json:"sku"`                 // Server type
+	CPUBrand         string            `json:"cpu_brand"`           // CPU brand
+	CPUModel         string            `json:"cpu_model"`           // CPU model
+	CPUClockSpeedGhz float64           `json:"cpu_clock_speed_ghz"` // CPU clock speed
+	CPUCores         int               `json:"cpu_cores"`           // Cores per CPU
+	CPUCount         int               `json:"cpu_count"`           // Number of CPUs
+	TotalRAMGB       int               `json:"total_ram_gb"`        // RAM
+	TotalNVMESizeGB  int               `json:"total_nvme_size_gb"`  // NVMe storage
+	TotalSSDSizeGB   int               `json:"total_ssd_size_gb"`   // SSD storage
+	TotalHDDSizeGB   int               `json:"total_hdd_size_gb"`   // HDD storage
+	RAIDEnabled      bool              `json:"raid_enabled"`        // Whether the server has RAID
+	NICSpeedMbps     int               `json:"nic_speed_mbps"`      // NIC speed
+	Metadata         []icsapi.Metadata `json:"metadata"`            // Metadata of every inventory entry, without duplicates
+	Locations        []skuLocation     `json:"locations"`           // Availability in each datacenter
+}
+
+// skuLocation is the availability of a server type in a datacenter
+type skuLocation struct {
+	LocationCode     string             `json:"location_code"`           // Location code
+	RegionID         int                `json:"region_id"`               // Region ID
+	Quantity         int                `json:"quantity"`                // Quantity available
+	AutoProvisionQty int                `json:"auto_provision_quantity"` // Quantity available for instant provisioning
+	Price            string             `json:"price"`                   // Monthly price
+	Currency         string             `json:"currency"`                // Currency of the price
+	Status           string             `json:"status"`                  // Inventory status
+	Addons           *icsapi.AddonTypes `json:"addons"`                  // Add-ons that can be ordered with the server
+	AddonsError      string             `json:"addons_error,omitempty"`  // Why the add-ons could not be retrieved
+}
+
+// skuDescribeCmd represents the sku describe command
+var skuDescribeCmd = &cobra.Command{
+	Use:   "describe [sku]",
+	Short: "Show the hardware, metadata and availability of a server type",
+	Long: `Show everything the inventory knows about a server type: CPU, RAM, disks, RAID,
+NIC speed and metadata, the quantity in each datacenter including the quantity
+available for instant provisioning, and the add-ons that can be ordered with it
+in each datacenter.`,
+	Example: `  # Describe a server type
+  ics-cli baremetal sku describe c1i.small
+
+  # Only in one datacenter
+  ics-cli baremetal sku describe c1i.small --datacenter NYC1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		datacenter, _ := cmd.Flags().GetString("datacenter")
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+
+		inventory, err := client.Inventory(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		var entries []icsapi.InventoryDetails
+		for _, item := range inventory {
+			if !strings.EqualFold(item.SkuProductName, args[0]) {
+				continue
+			}
+			if datacenter != "" && !strings.EqualFold(item.LocationCode, datacenter) {
+				continue
+			}
+			entries = append(entries, item)
+		}
+
+		if len(entries) == 0 {
+			if datacenter != "" {
+				return &exitError{code: exitNotFound, err: fmt.Errorf("server type %s is not offered in %s", args[0], datacenter)}
+			}
+			return &exitError{code: exitNotFound, err: fmt.Errorf("server type %s not found in the inventory", args[0])}
+		}
+
+		description := describeSku(entries)
+
+		// Add-ons depend on the datacenter
+		for i := range description.Locations {
+			location := &description.Locations[i]
+			location.Addons, err = client.Addons(cmd.Context(), description.SKU, location.LocationCode)
+			if err != nil {
+				location.AddonsError = err.Error()
+			}
+		}
+
+		if !isTableOutput() {
+			return printStructured(description)
+		}
+
+		printSkuDescription(description)
+		return nil
+	},
+}
+
+func init() {
+	baremetalSkuCmd.AddCommand(skuDescribeCmd)
+
+	skuDescribeCmd.Flags().String("datacenter", "", "Only show the server type in this datacenter (e.g., NYC1)")
+}
+
+// describeSku aggregates the inventory entries of a server type. The hardware is taken from
+// the first entry, the quantities are summed for each location and the metadata is merged.
+func describeSku(entries []icsapi.InventoryDetails) skuDescription {
+	first := entries[0]
+	description := skuDescription{
+		SKU:              first.SkuProductName,
+		CPUBrand:         first.CPUBrand,
+		CPUModel:         first.CPUModel,
+		CPUClockSpeedGhz: first.CPUClockSpeedGhz,
+		CPUCores:         first.CPUCores,
+		CPUCount:         first.CPUCount,
+		TotalRAMGB:       first.TotalRAMGB,
+		TotalNVMESizeGB:  first.TotalNVMESizeGB,
+		TotalSSDSizeGB:   first.TotalSSDSizeGB,
+		TotalHDDSizeGB:   first.TotalHDDSizeGB,
+		RAIDEnabled:      first.RAIDEnabled,
+		NICSpeedMbps:     first.NICSpeedMbps,
+		Metadata:         []icsapi.Metadata{},
+	}
+
+	locations := make(map[string]int)
+	for _, entry := range entries {
+		for _, metadata := range entry.Metadata {
+			if !slices.Contains(description.Metadata, metadata) {
+				description.Metadata = append(description.Metadata, metadata)
+			}
+		}
+
+		if i, exists := locations[entry.LocationCode]; exists {
+			description.Locations[i].Quantity += entry.Quantity
+			description.Locations[i].AutoProvisionQty += entry.AutoProvisionQty
+			continue
+		}
+		locations[entry.LocationCode] = len(description.Locations)
+		description.Locations = append(description.Locations, skuLocation{
+			LocationCode:     entry.LocationCode,
+			RegionID:         entry.RegionID,
+			Quantity:         entry.Quantity,
+			AutoProvisionQty: entry.AutoProvisionQty,
+			Price:            entry.Price,
+			Currency:         entry.CurrencyCode,
+			Status:           entry.Status,
+		})
+	}
+
+	slices.SortFunc(description.Locations, func(a, b skuLocation) int {
+		return cmp.Compare(a.LocationCode, b.LocationCode)
+	})
+
+	return description
+}
+
+// printSkuDescription prints the hardware, metadata, availability and add-ons of a server type
+func printSkuDescription(description skuDescription) {
+	hardware := icsapi.InventoryDetails{
+		CPUCores:        description.CPUCores,
+		CPUCount:        description.CPUCount,
+		TotalNVMESizeGB: description.TotalNVMESizeGB,
+		TotalSSDSizeGB:  description.TotalSSDSizeGB,
+		TotalHDDSizeGB:  description.TotalHDDSizeGB,
+	}
+
+	cpu := strings.TrimSpace(description.CPUBrand + " " + description.CPUModel)
+	if description.CPUClockSpeedGhz > 0 {
+		cpu = fmt.Sprintf("%s @ %.2f GHz", cpu, description.CPUClockSpeedGhz)
+	}
+	cores := strconv.Itoa(totalCores(hardware))
+	if description.CPUCount > 1 {
+		cores = fmt.Sprintf("%s (%dx%d)", cores, description.CPUCount, description.CPUCores)
+	}
+	raid := "No"
+	if description.RAIDEnabled {
+		raid = "Yes"
+	}
+
+	fmt.Printf("%s %s\n", BlueHeading("Server Type:"), WhiteText(description.SKU))
+	fmt.Printf("%s %s\n", BlueHeading("CPU:"), WhiteText(cpu))
+	fmt.Printf("%s %s\n", BlueHeading("Cores:"), WhiteText(cores))
+	fmt.Printf("%s %s\n", BlueHeading("RAM:"), WhiteText(fmt.Sprintf("%d GB", description.TotalRAMGB)))
+	fmt.Printf("%s %s\n", BlueHeading("Storage:"), WhiteText(formatStorage(hardware)))
+	fmt.Printf("%s %s\n", BlueHeading("RAID:"), WhiteText(raid))
+	fmt.Printf("%s %s\n", BlueHeading("NIC:"), WhiteText(formatNICSpeed(description.NICSpeedMbps)))
+
+	headerFmt := color.New(color.FgBlue, color.Bold).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	fmt.Println(BlueHeading("\n=== METADATA ==="))
+	if len(description.Metadata) == 0 {
+		fmt.Println(WhiteText("None"))
+	} else {
+		tbl := table.New("Name", "Description", "Value")
+		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+		for _, metadata := range description.Metadata {
+			tbl.AddRow(metadata.Name, metadata.Description, metadata.Value)
+		}
+		tbl.Print()
+	}
+
+	fmt.Println(BlueHeading("\n=== AVAILABILITY ==="))
+	tbl := table.New("Location", "Price", "Available Quantity", "Instant Provision", "Status")
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	for _, location := range description.Locations {
+		tbl.AddRow(
+			location.LocationCode,
+			strings.TrimSpace(location.Price+" "+location.Currency),
+			strconv.Itoa(location.Quantity),
+			strconv.Itoa(location.AutoProvisionQty),
+			location.Status,
+		)
+	}
+	tbl.Print()
+
+	for _, location := range description.Locations {
+		fmt.Println(BlueHeading(fmt.Sprintf("\n=== ADD-ONS IN %s ===", location.LocationCode)))
+		if location.Addons == nil {
+			fmt.Println(RedText("Unable to retrieve add-ons: " + location.AddonsError))
+			continue
+		}
+		printAddons(location.Addons)
+	}
+}
@@ -3,53 +3,53 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strconv"
 
 	"github.com/spf13/cobra"
 )
 
 // solCmd represents the sol command
 var solCmd = &cobra.Command{
-	Use:   "sol [serviceID]",
+	Use:   "sol [server]",
 	Short: "Generate a Serial Over Lan (SOL) session",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		// Get service ID from arguments
-		serviceID := args[0]
-
-		// Validate service ID is a number
-		if _, err := strconv.Atoi(serviceID); err != nil {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be a number")
-			return
+		client, err := newAPIClient()
+		if err != nil {
+			return err
 		}
 
-		// Step 1: Get the server ID from service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
+		// Resolve the server argument
+		server, err := client.ResolveServer(cmd.Context(), args[0])
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
 		// Get the SOL access link
-		solLink, err := getSolResponse(serverID)
+		solLink, err := client.SOL(cmd.Context(), server.ID)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error getting SOL access link:", err)
-			return
+			return fmt.Errorf("failed to get SOL access link: %w", err)
 		}
 
 		// Print the SOL access link
-		fmt.Printf("%s %s\n", BlueHeading("SOL Access Link:"), WhiteText(solLink))
+		if isTableOutput() {
+			fmt.Printf("%s %s\n", BlueHeading("SOL Access Link:"), WhiteText(solLink))
+		} else {
+			if err := printStructured(linkResult{ServiceID: server.ServiceID, Type: "sol", Link: solLink}); err != nil {
+				fmt.Fprintln(os.Stderr, "Error writing output:", err)
+			}
+		}
 
 		// If --open flag is specified, try to open the link in a browser
 		openInBrowser, _ := cmd.Flags().GetBool("browser")
 		if !openInBrowser {
-			fmt.Println("Opening SOL access link in your default browser...")
+			fmt.Fprintln(os.Stderr, "Opening SOL access link in your default browser...")
 			err := openBrowser(solLink)
 			if err != nil {
 				fmt.Fprintln(os.Stderr, "Error opening browser:", err)
 			}
 		}
+		return nil
 	},
 }
 
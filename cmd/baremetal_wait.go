@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/spf13/cobra"
+)
+
+// Defaults of the --timeout and --interval flags
+const (
+	defaultWaitTimeout      = 10 * time.Minute
+	defaultProvisionTimeout = 60 * time.Minute
+	defaultWaitInterval     = 5 * time.Second
+)
+
+// waitCondition is a state the wait command can wait for
+type waitCondition struct {
+	state   string // State named in messages, e.g. "power on"
+	success string // Result message once a server reaches the state
+	check   func(ctx context.Context, client *icsapi.Client, server icsapi.Server) (ready bool, status string, err error)
+}
+
+// waitConditions are the values of the --for flag
+var waitConditions = map[string]waitCondition{
+	"provisioned": {
+		state:   "provisioning",
+		success: "Server is provisioned.",
+		check: func(ctx context.Context, client *icsapi.Client, server icsapi.Server) (bool, string, error) {
+			detail, err := client.GetServer(ctx, server.ID)
+			if err != nil {
+				return false, "", err
+			}
+			return !detail.ProvisioningStatus.IsProvisioning, detail.ProvisioningStatus.StatusMessage, nil
+		},
+	},
+	"powered-on":  powerCondition(true),
+	"powered-off": powerCondition(false),
+}
+
+// powerCondition returns the condition of a server being powered on or off
+func powerCondition(poweredOn bool) waitCondition {
+	return waitCondition{
+		state:   "power " + onOff(poweredOn),
+		success: fmt.Sprintf("Server is powered %s.", onOff(poweredOn)),
+		check: func(ctx context.Context, client *icsapi.Client, server icsapi.Server) (bool, string, error) {
+			isPoweredOn, err := client.PowerStatus(ctx, server.ID)
+			if err != nil {
+				return false, "", err
+			}
+			return isPoweredOn == poweredOn, "Powered " + onOff(isPoweredOn), nil
+		},
+	}
+}
+
+// waitState tracks a server in the wait command
+type waitState struct {
+	ready  bool   // Whether the server reached the condition
+	status string // Last status message reported
+	err    error  // Error that stopped the wait, if any
+}
+
+// bmWaitCmd represents the wait command
+var bmWaitCmd = &cobra.Command{
+	Use:   "wait [server...]",
+	Short: "Wait for baremetal servers to finish provisioning or reach a power state",
+	Long: `Wait for one or more servers to finish provisioning, or to be powered on or off.
+
+Servers are selected like the power commands do. Their status is polled every --interval,
+and status message changes are printed on stderr as they happen. The command returns once
+every server reaches the condition, and exits with code 8 if some have not after --timeout.`,
+	Example: `  # Wait for a reinstall to complete
+  ics-cli baremetal wait web-01
+
+  # Wait for every server in NYC1 to be powered on
+  ics-cli baremetal wait --site NYC1 --for powered-on --timeout 15m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("for")
+		condition, ok := waitConditions[name]
+		if !ok {
+			return usageError("invalid condition %q. Must be one of: provisioned, powered-on, powered-off", name)
+		}
+
+		if err := applyConfigDefault(cmd, "parallel", "parallel"); err != nil {
+			return err
+		}
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		if parallel < 1 {
+			return usageError("--parallel must be at least 1")
+		}
+
+		timeout, interval, err := getPollFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+
+		servers, err := selectServers(cmd, client, args)
+		if err != nil {
+			return err
+		}
+
+		states, err := waitForServers(cmd.Context(), client, servers, condition, parallel, timeout, interval)
+		if err != nil {
+			return err
+		}
+
+		results := make([]bulkResult, len(servers))
+		timedOut := false
+		for i, server := range servers {
+			results[i] = bulkResult{server: server, success: states[i].ready, err: states[i].err}
+			if errors.Is(states[i].err, icsapi.ErrWaitTimeout) {
+				timedOut = true
+			}
+		}
+
+		if !isBulkSelection(cmd, args) {
+			if results[0].err != nil {
+				return results[0].err
+			}
+			return printActionResult(newActionResult(servers[0].ServiceID, "wait", true, condition.success))
+		}
+
+		err = printBulkResults(powerAction{name: "wait", success: condition.success}, results)
+		if err != nil && timedOut {
+			return &exitError{code: exitTimeout, err: err, silent: true}
+		}
+		return err
+	},
+}
+
+func init() {
+	baremetalCmd.AddCommand(bmWaitCmd)
+
+	bmWaitCmd.Flags().String("for", "provisioned", "Condition to wait for: provisioned, powered-on or powered-off")
+	addServerSelectionFlags(bmWaitCmd)
+	addPollFlags(bmWaitCmd, defaultProvisionTimeout)
+}
+
+// waitForServers polls the servers until they all reach the condition or the timeout
+// expires. The returned states are in the order of servers; the servers that did not
+// reach the condition in time have an error matching icsapi.ErrWaitTimeout.
+func waitForServers(ctx context.Context, client *icsapi.Client, servers []icsapi.Server, condition waitCondition, parallel int, timeout, interval time.Duration) ([]waitState, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	states := make([]waitState, len(servers))
+	byID := make(map[string]*waitState, len(servers))
+	for i, server := range servers {
+		byID[server.ID] = &states[i]
+	}
+
+	var status *progress
+	defer func() {
+		if status != nil {
+			status.Stop()
+		}
+	}()
+
+	for {
+		var pending []icsapi.Server
+		for i, server := range servers {
+			if !states[i].ready && states[i].err == nil {
+				pending = append(pending, server)
+			}
+		}
+		if len(pending) == 0 {
+			return states, nil
+		}
+
+		message := fmt.Sprintf("Waiting for %s of %s...", condition.state, servers[0].Hostname)
+		if len(servers) > 1 {
+			message = fmt.Sprintf("Waiting for %s: %d of %d servers ready...", condition.state, len(servers)-len(pending), len(servers))
+		}
+		if status == nil {
+			status = newProgress(message)
+		} else {
+			status.Update(message)
+		}
+
+		runBulk(waitCtx, pending, parallel, func(ctx context.Context, server icsapi.Server) (bool, error) {
+			state := byID[server.ID]
+			ready, message, err := condition.check(ctx, client, server)
+			switch {
+			case errors.Is(err, icsapi.ErrUnauthorized) || errors.Is(err, icsapi.ErrNotFound):
+				state.err = err
+			case err != nil:
+				// The status may be briefly unavailable, keep polling
+			default:
+				if message != "" && message != state.status {
+					status.Log(fmt.Sprintf("%s (%d): %s", server.Hostname, server.ServiceID, message))
+				}
+				state.ready, state.status = ready, message
+			}
+			return ready, err
+		})
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("stopped waiting for %s: %w", condition.state, ctx.Err())
+			}
+			for i, server := range servers {
+				if !states[i].ready && states[i].err == nil {
+					states[i].err = fmt.Errorf("%s (%d): %w for %s", server.Hostname, server.ServiceID, icsapi.ErrWaitTimeout, condition.state)
+				}
+			}
+			return states, nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// addWaitFlags adds the --wait, --timeout and --interval flags to a command
+func addWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "Wait until the server reaches the expected power state")
+	addPollFlags(cmd, defaultWaitTimeout)
+}
+
+// addPollFlags adds the --timeout and --interval flags of a command polling a status
+func addPollFlags(cmd *cobra.Command, timeout time.Duration) {
+	cmd.Flags().Duration("timeout", timeout, "Maximum time to wait")
+	cmd.Flags().Duration("interval", defaultWaitInterval, "Time between status checks")
+}
+
+// getWaitFlags returns the values of the --wait, --timeout and --interval flags
+func getWaitFlags(cmd *cobra.Command) (wait bool, timeout, interval time.Duration, err error) {
+	wait, _ = cmd.Flags().GetBool("wait")
+	timeout, interval, err = getPollFlags(cmd)
+	return wait, timeout, interval, err
+}
+
+// getPollFlags returns the values of the --timeout and --interval flags, which
+// default to the wait_timeout and wait_interval config keys
+func getPollFlags(cmd *cobra.Command) (timeout, interval time.Duration, err error) {
+	if err := applyConfigDefault(cmd, "timeout", "wait_timeout"); err != nil {
+		return 0, 0, err
+	}
+	if err := applyConfigDefault(cmd, "interval", "wait_interval"); err != nil {
+		return 0, 0, err
+	}
+
+	timeout, _ = cmd.Flags().GetDuration("timeout")
+	interval, _ = cmd.Flags().GetDuration("interval")
+
+	if timeout <= 0 {
+		return 0, 0, usageError("--timeout must be positive")
+	}
+	if interval <= 0 {
+		return 0, 0, usageError("--interval must be positive")
+	}
+
+	return timeout, interval, nil
+}
+
+// waitForPower waits for a server to reach the power state of an action. A reboot is
+// over once the server has been seen off and then on again.
+func waitForPower(ctx context.Context, client *icsapi.Client, serverID string, action powerAction, timeout, interval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if action.cycle {
+		return client.WaitForPowerCycle(ctx, serverID, interval)
+	}
+	return client.WaitForPowerState(ctx, serverID, action.waitFor, interval)
+}
+
+// onOff describes a power state
+func onOff(poweredOn bool) string {
+	if poweredOn {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and edit the config file",
+	Long: `View and edit the settings of the config file, $HOME/.ics-cli.yaml by default.
+
+Settings are read and written in the active profile: the profile given with --profile
+or ICS_PROFILE, otherwise the current profile. Values are checked against the known
+keys before they are saved. Run 'ics-cli config list' to see every key, its value and
+where the value comes from, and 'ics-cli config validate' to check the whole file.
+
+Values are taken, in order of precedence, from flags, ICS_<KEY> environment variables
+such as ICS_WAIT_TIMEOUT, the active profile, the top-level keys of the config file and
+built-in defaults.`,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
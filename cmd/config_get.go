@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print the effective value of a setting",
+	Long: `Print the effective value of a setting for the active profile, taking flags,
+environment variables and defaults into account. Secrets are redacted.`,
+	Example: `  # Print the API URL in use
+  ics-cli config get api_url
+
+  # Show where the value comes from
+  ics-cli config get api_url -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := findConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		entry := resolveConfigKey(key, config)
+		if !isTableOutput() {
+			return printStructured(entry)
+		}
+
+		fmt.Println(entry.Value)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+)
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every setting with its value and source",
+	Long: `List every setting of the active profile with its effective value and where it
+comes from: a flag, an environment variable, the profile, the file or the default.
+Secrets are redacted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		var entries []configEntry
+		for _, key := range configSchema {
+			entries = append(entries, resolveConfigKey(key, config))
+		}
+
+		if !isTableOutput() {
+			return printStructured(entries)
+		}
+
+		showActiveProfile()
+
+		headerFmt := color.New(color.FgBlue, color.Bold).SprintfFunc()
+		columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+		tbl := table.New("Key", "Value", "Source")
+		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+		for _, entry := range entries {
+			value := entry.Value
+			if value == "" {
+				value = "-"
+			}
+			tbl.AddRow(entry.Key, value, entry.Source)
+		}
+
+		tbl.Print()
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configListCmd)
+}
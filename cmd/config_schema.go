@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// redactedValue replaces secrets in the output of the config commands
+const redactedValue = "********"
+
+// configKey is a setting of the config file, which can also be set in a profile
+type configKey struct {
+	name         string             // Key in the config file
+	description  string             // What the setting does
+	kind         string             // Type of the value: "string", "int" or "duration"
+	values       []string           // Allowed values, if limited
+	defaultValue string             // Value used when the key is not set, for display
+	flag         string             // Global flag overriding the key, if any
+	positive     bool               // Whether a number or duration must be above 0
+	secret       bool               // Whether the value is redacted
+	validate     func(string) error // Extra check of the value, if any
+}
+
+// configSchema declares the settings of the config file. Names starting with "watch_"
+// are reserved for the ICS_WATCH_ variables of inventory watch hooks.
+var configSchema = []configKey{
+	{name: "api_key", description: "API key in plaintext. Use 'ics-cli auth login' to save it encrypted", kind: "string", secret: true},
+	{name: "api_key_ref", description: "Reference to the API key in a credential store, e.g. file:default", kind: "string", validate: validateCredentialRef},
+	{name: "api_url", description: "Base URL of the API", kind: "string", defaultValue: icsapi.DefaultBaseURL, flag: "api-url", validate: validateAPIURL},
+	{name: "credential_store", description: "Where 'ics-cli auth login' saves API keys", kind: "string", values: credentialStoreNames(), defaultValue: defaultCredentialStore},
+	{name: "credential_cache_ttl", description: "How long an unlocked credential file stays unlocked, 0 to always ask", kind: "duration", defaultValue: defaultCredentialCacheTTL.String()},
+	{name: "credential_helper", description: "Command printing the API key as JSON, run with the get argument", kind: "string"},
+	{name: "site", description: "Default site of 'baremetal list' and datacenter of new orders, e.g. NYC1", kind: "string"},
+	{name: "output", description: "Default output format", kind: "string", values: outputFormats, defaultValue: "table", flag: "output"},
+	{name: "retries", description: "Number of retries for idempotent API requests on transient failures", kind: "int", defaultValue: strconv.Itoa(icsapi.DefaultRetryPolicy.MaxRetries), flag: "retries"},
+	{name: "wait_timeout", description: "Default --timeout of commands that wait", kind: "duration", positive: true, defaultValue: fmt.Sprintf("%s, %s for provisioning", defaultWaitTimeout, defaultProvisionTimeout)},
+	{name: "wait_interval", description: "Default --interval of commands that wait", kind: "duration", positive: true, defaultValue: defaultWaitInterval.String()},
+	{name: "parallel", description: "Default --parallel of commands acting on several servers", kind: "int", positive: true, defaultValue: strconv.Itoa(defaultParallel)},
+}
+
+// configFileKeys are the top-level keys of the config file that are not settings
+var configFileKeys = []string{"profiles", "current_profile"}
+
+// findConfigKey returns the declared setting with the given name
+func findConfigKey(name string) (configKey, error) {
+	for _, key := range configSchema {
+		if key.name == name {
+			return key, nil
+		}
+	}
+
+	var names []string
+	for _, key := range configSchema {
+		names = append(names, key.name)
+	}
+	return configKey{}, usageError("unknown config key %q. Must be one of: %s", name, strings.Join(names, ", "))
+}
+
+// check validates a value of the setting
+func (k configKey) check(value string) error {
+	minimum := 0
+	if k.positive {
+		minimum = 1
+	}
+
+	switch k.kind {
+	case "int":
+		if n, err := strconv.Atoi(value); err != nil || n < minimum {
+			return fmt.Errorf("%s must be a whole number of at least %d, got %q", k.name, minimum, value)
+		}
+	case "duration":
+		if d, err := time.ParseDuration(value); err != nil || d < time.Duration(minimum) {
+			return fmt.Errorf("%s must be a duration such as 30s or 10m, got %q", k.name, value)
+		}
+	}
+
+	if len(k.values) > 0 && !slices.Contains(k.values, value) {
+		return fmt.Errorf("%s must be one of: %s, got %q", k.name, strings.Join(k.values, ", "), value)
+	}
+	if k.validate != nil {
+		return k.validate(value)
+	}
+	return nil
+}
+
+// parse converts a valid value to the type saved in the config file
+func (k configKey) parse(value string) interface{} {
+	if k.kind == "int" {
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+	return value
+}
+
+// envVar returns the environment variable overriding the setting
+func (k configKey) envVar() string {
+	return envVarName(k.name)
+}
+
+// configEntry is the effective value of a setting and where it comes from
+type configEntry struct {
+	Key    string `json:"key"`    // Config key
+	Value  string `json:"value"`  // Effective value, with secrets redacted
+	Source string `json:"source"` // Where the value comes from: flag, env, profile, file or default
+}
+
+// resolveConfigKey returns the effective value of a setting for the active profile.
+// Flags take precedence over environment variables, the profile, the file and the default.
+func resolveConfigKey(key configKey, config *configFile) configEntry {
+	entry := configEntry{Key: key.name, Value: key.defaultValue, Source: "default"}
+
+	if value, source, ok := configOverride(key); ok {
+		entry.Value, entry.Source = value, source
+	} else if value, ok := config.profile(activeProfile)[key.name]; ok && activeProfile != defaultProfile {
+		entry.Value, entry.Source = fmt.Sprint(value), "profile "+activeProfile
+	} else if value, ok := config.profile(defaultProfile)[key.name]; ok && (activeProfile == defaultProfile || !slices.Contains(profileAccountKeys, key.name)) {
+		entry.Value, entry.Source = fmt.Sprint(value), "file"
+	}
+
+	if key.secret && entry.Value != "" {
+		entry.Value = redactedValue
+	}
+	return entry
+}
+
+// configOverride returns the value of a setting from its flag or environment variables, if set
+func configOverride(key configKey) (string, string, bool) {
+	if key.flag != "" {
+		if flag := rootCmd.PersistentFlags().Lookup(key.flag); flag != nil && flag.Changed {
+			return flag.Value.String(), "flag --" + key.flag, true
+		}
+	}
+	if value := os.Getenv(key.envVar()); value != "" {
+		return value, "env " + key.envVar(), true
+	}
+	return "", "", false
+}
+
+// validateConfigFile checks every key and value of the config file against the schema
+func validateConfigFile(config *configFile) []string {
+	var problems []string
+
+	checkSettings := func(where string, settings map[string]interface{}) {
+		for _, name := range slices.Sorted(settingNames(settings)) {
+			key, err := findConfigKey(name)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: unknown key %q", where, name))
+				continue
+			}
+			if err := key.check(fmt.Sprint(settings[name])); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", where, err))
+			}
+		}
+	}
+
+	checkSettings("top-level", config.profile(defaultProfile))
+
+	if profiles, ok := config.settings["profiles"]; ok && profiles != nil {
+		if _, ok := profiles.(map[string]interface{}); !ok {
+			problems = append(problems, "profiles must be a map of profile names to settings")
+		}
+	}
+	for _, name := range slices.Sorted(settingNames(config.profiles())) {
+		if !profileNamePattern.MatchString(name) {
+			problems = append(problems, fmt.Sprintf("profile %q: invalid name, use letters, digits, '-' and '_'", name))
+		}
+		settings, ok := config.profiles()[name].(map[string]interface{})
+		if !ok && config.profiles()[name] != nil {
+			problems = append(problems, fmt.Sprintf("profile %s: must be a map of settings", name))
+			continue
+		}
+		checkSettings("profile "+name, settings)
+	}
+
+	if current := config.currentProfile(); !config.hasProfile(current) {
+		problems = append(problems, fmt.Sprintf("current_profile: profile %q does not exist", current))
+	}
+
+	return problems
+}
+
+// settingNames returns the keys of a settings map, without the profiles and current_profile keys
+func settingNames(settings map[string]interface{}) func(yield func(string) bool) {
+	return func(yield func(string) bool) {
+		for key := range settings {
+			if slices.Contains(configFileKeys, key) {
+				continue
+			}
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// applyConfigDefault sets a flag to the value of a config key, unless it was given on the command line
+func applyConfigDefault(cmd *cobra.Command, flagName, key string) error {
+	flag := cmd.Flags().Lookup(flagName)
+	if flag == nil || flag.Changed || !viper.IsSet(key) || viper.GetString(key) == "" {
+		return nil
+	}
+	if err := flag.Value.Set(viper.GetString(key)); err != nil {
+		return usageError("invalid %s in config: %v", key, err)
+	}
+	return nil
+}
+
+// credentialStoreNames returns the values of the credential_store key
+func credentialStoreNames() []string {
+	names := []string{plaintextCredentialStore}
+	for name := range credentialStores {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// validateCredentialRef checks an api_key_ref value
+func validateCredentialRef(ref string) error {
+	store, _, err := parseCredentialRef(ref)
+	if err != nil {
+		return err
+	}
+	if _, ok := credentialStores[store]; !ok {
+		return fmt.Errorf("api_key_ref refers to unknown credential store %q", store)
+	}
+	return nil
+}
+
+// validateAPIURL checks an api_url value
+func validateAPIURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("api_url must be an http or https URL, got %q", value)
+	}
+	return nil
+}
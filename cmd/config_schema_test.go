@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestConfigKeyCheck(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{key: "retries", value: "0"},
+		{key: "retries", value: "-1", wantErr: true},
+		{key: "retries", value: "two", wantErr: true},
+		{key: "parallel", value: "4"},
+		{key: "parallel", value: "0", wantErr: true},
+		{key: "wait_timeout", value: "10m"},
+		{key: "wait_timeout", value: "0s", wantErr: true},
+		{key: "wait_timeout", value: "10", wantErr: true},
+		{key: "credential_cache_ttl", value: "0s"},
+		{key: "output", value: "json"},
+		{key: "output", value: "xml", wantErr: true},
+		{key: "credential_store", value: "plaintext"},
+		{key: "credential_store", value: "keychain", wantErr: true},
+		{key: "api_url", value: "https://api.example.com/v1"},
+		{key: "api_url", value: "ftp://api.example.com", wantErr: true},
+		{key: "api_url", value: "api.example.com", wantErr: true},
+		{key: "api_key_ref", value: "file:default"},
+		{key: "api_key_ref", value: "file", wantErr: true},
+		{key: "api_key_ref", value: "vault:default", wantErr: true},
+		{key: "site", value: "anything"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			key, err := findConfigKey(tt.key)
+			if err != nil {
+				t.Fatalf("findConfigKey(%q) error = %v", tt.key, err)
+			}
+			if err := key.check(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("check(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := findConfigKey("api_token"); exitCode(err) != exitUsage {
+		t.Errorf("findConfigKey() of an unknown key, error = %v, want a usage error", err)
+	}
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     []string
+	}{
+		{
+			name: "valid",
+			settings: map[string]interface{}{
+				"api_key_ref":     "file:default",
+				"retries":         3,
+				"current_profile": "acme",
+				"profiles":        map[string]interface{}{"acme": map[string]interface{}{"site": "NYC1"}, "empty": nil},
+			},
+		},
+		{
+			name:     "unknown key and invalid value",
+			settings: map[string]interface{}{"api_token": "x", "parallel": 0},
+			want: []string{
+				`top-level: unknown key "api_token"`,
+				`top-level: parallel must be a whole number of at least 1, got "0"`,
+			},
+		},
+		{
+			name: "invalid profiles",
+			settings: map[string]interface{}{
+				"current_profile": "missing",
+				"profiles":        map[string]interface{}{"bad name": map[string]interface{}{}, "flat": "NYC1", "acme": map[string]interface{}{"retries": "many"}},
+			},
+			want: []string{
+				`profile acme: retries must be a whole number of at least 0, got "many"`,
+				`profile "bad name": invalid name, use letters, digits, '-' and '_'`,
+				"profile flat: must be a map of settings",
+				`current_profile: profile "missing" does not exist`,
+			},
+		},
+		{
+			name:     "profiles not a map",
+			settings: map[string]interface{}{"profiles": []interface{}{"acme"}},
+			want:     []string{"profiles must be a map of profile names to settings"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateConfigFile(&configFile{settings: tt.settings})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("validateConfigFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Set a setting in the active profile",
+	Long: `Set a setting in the active profile of the config file. The value is checked
+against the type and allowed values of the key before it is saved.
+
+API keys are not set with this command: use 'ics-cli auth login', which verifies
+the key and encrypts it.`,
+	Example: `  # Create servers in NYC1 unless --datacenter is given
+  ics-cli config set site NYC1
+
+  # Wait up to 30 minutes in the acme profile
+  ics-cli config set wait_timeout 30m --profile acme`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := findConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		if key.secret {
+			return usageError("%s cannot be set with config set. Run 'ics-cli auth login' instead", key.name)
+		}
+		if err := key.check(args[1]); err != nil {
+			return usageError("%v", err)
+		}
+		if err := validateProfileName(activeProfile); err != nil {
+			return err
+		}
+
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		config.setProfileValue(activeProfile, key.name, key.parse(args[1]))
+		if err := config.save(); err != nil {
+			return err
+		}
+
+		return printActionResult(actionResult{Action: "set", Success: true, Message: fmt.Sprintf("Set %s to %s in profile %s", key.name, args[1], activeProfile)})
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+}
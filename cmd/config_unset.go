@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// configUnsetCmd represents the config unset command
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset [key]",
+	Short: "Remove a setting from the active profile",
+	Long: `Remove a setting from the active profile of the config file. A named profile then
+uses the top-level value of the key, if any, and the default profile the built-in
+default. API keys are removed with 'ics-cli auth logout'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := findConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		if key.secret || key.name == "api_key_ref" {
+			return usageError("%s cannot be unset with config unset. Run 'ics-cli auth logout' instead", key.name)
+		}
+
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		if _, ok := config.profile(activeProfile)[key.name]; !ok {
+			return &exitError{code: exitNotFound, err: fmt.Errorf("%s is not set in profile %s", key.name, activeProfile)}
+		}
+
+		config.unsetProfileValue(activeProfile, key.name)
+		if err := config.save(); err != nil {
+			return err
+		}
+
+		return printActionResult(actionResult{Action: "unset", Success: true, Message: fmt.Sprintf("Removed %s from profile %s", key.name, activeProfile)})
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configUnsetCmd)
+}
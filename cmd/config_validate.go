@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// configValidation is the machine readable result of config validate
+type configValidation struct {
+	Path     string   `json:"path"`     // Path of the config file
+	Valid    bool     `json:"valid"`    // Whether the file has no problems
+	Problems []string `json:"problems"` // Every problem found
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file for errors",
+	Long: `Check the config file for YAML errors, unknown keys, values of the wrong type or
+out of range, invalid profile names and a current profile that does not exist.
+Every problem is reported, and the command fails if there is any.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configFilePath()
+		if err != nil {
+			return err
+		}
+
+		result := configValidation{Path: path, Problems: []string{}}
+		config, err := readConfigFile()
+		if err != nil {
+			result.Problems = append(result.Problems, err.Error())
+		} else {
+			result.Problems = append(result.Problems, validateConfigFile(config)...)
+		}
+		result.Valid = len(result.Problems) == 0
+
+		if !isTableOutput() {
+			if err := printStructured(result); err != nil {
+				return err
+			}
+		} else if result.Valid {
+			fmt.Println(GreenText(fmt.Sprintf("%s is valid", path)))
+		} else {
+			fmt.Printf("%s %s\n", BlueHeading("Config file:"), WhiteText(path))
+			for _, problem := range result.Problems {
+				fmt.Printf("  %s\n", RedText(problem))
+			}
+		}
+
+		if !result.Valid {
+			return &exitError{code: exitFailure, err: fmt.Errorf("found %d problems in the config file", len(result.Problems)), silent: true}
+		}
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"maps"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// configViewCmd represents the config view command
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the config file with secrets redacted",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		settings := redactSettings(config.settings)
+		if profiles, ok := settings["profiles"].(map[string]interface{}); ok {
+			redacted := make(map[string]interface{}, len(profiles))
+			for name, profile := range profiles {
+				if profile, ok := profile.(map[string]interface{}); ok {
+					redacted[name] = redactSettings(profile)
+				} else {
+					redacted[name] = profile
+				}
+			}
+			settings["profiles"] = redacted
+		}
+
+		if !isTableOutput() {
+			return printStructured(settings)
+		}
+
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(settings)
+	},
+}
+
+// redactSettings returns a copy of settings with the values of secret keys redacted
+func redactSettings(settings map[string]interface{}) map[string]interface{} {
+	redacted := maps.Clone(settings)
+	if redacted == nil {
+		redacted = make(map[string]interface{})
+	}
+	for _, key := range configSchema {
+		if value, ok := redacted[key.name]; ok && key.secret && value != "" {
+			redacted[key.name] = redactedValue
+		}
+	}
+	return redacted
+}
+
+func init() {
+	configCmd.AddCommand(configViewCmd)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// defaultCredentialStore is the credential store API keys are saved to unless the
+// credential_store config key selects another one
+const defaultCredentialStore = "file"
+
+// plaintextCredentialStore keeps API keys in the config file, as earlier versions did
+const plaintextCredentialStore = "plaintext"
+
+// credentialStore is a backend holding API keys outside the config file. The config
+// file keeps a reference to the key, "<store>:<id>", in the api_key_ref key.
+type credentialStore interface {
+	get(id string) (string, error) // Returns the API key saved under id
+	set(id, apiKey string) error   // Saves an API key under id
+	delete(id string) error        // Removes the API key saved under id, if any
+}
+
+// credentialStores are the available credential stores, by name
+var credentialStores = map[string]func() (credentialStore, error){
+	"file": newFileCredentialStore,
+}
+
+// openCredentialStore returns the credential store with the given name
+func openCredentialStore(name string) (credentialStore, error) {
+	open, ok := credentialStores[name]
+	if !ok {
+		var names []string
+		for name := range credentialStores {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return nil, usageError("unknown credential store %q. Must be one of: %s, %s", name, strings.Join(names, ", "), plaintextCredentialStore)
+	}
+	return open()
+}
+
+// selectedCredentialStore returns the name of the store new API keys are saved to
+func selectedCredentialStore() string {
+	if name := viper.GetString("credential_store"); name != "" {
+		return name
+	}
+	return defaultCredentialStore
+}
+
+// parseCredentialRef splits a reference into the name of its store and the ID of the key
+func parseCredentialRef(ref string) (string, string, error) {
+	store, id, ok := strings.Cut(ref, ":")
+	if !ok || store == "" || id == "" {
+		return "", "", fmt.Errorf("invalid api_key_ref %q, expected <store>:<id>", ref)
+	}
+	return store, id, nil
+}
+
+// lookupCredential returns the API key a reference points to
+func lookupCredential(ref string) (string, error) {
+	storeName, id, err := parseCredentialRef(ref)
+	if err != nil {
+		return "", err
+	}
+	store, err := openCredentialStore(storeName)
+	if err != nil {
+		return "", err
+	}
+	return store.get(id)
+}
+
+// configuredAPIKey returns the API key of the active profile: a key set with
+// ICS_API_KEY or in the config file, or else the key api_key_ref points to
+func configuredAPIKey() (string, error) {
+	if apiKey := viper.GetString("api_key"); apiKey != "" {
+		return apiKey, nil
+	}
+	if ref := viper.GetString("api_key_ref"); ref != "" {
+		return lookupCredential(ref)
+	}
+	return "", nil
+}
+
+// saveAPIKey saves the API key of a profile to the selected credential store and
+// points the profile to it, or keeps it in the config file with the plaintext store
+func saveAPIKey(config *configFile, profile, apiKey string) error {
+	storeName := selectedCredentialStore()
+	if storeName == plaintextCredentialStore {
+		deleteAPIKey(config, profile)
+		config.setProfileValue(profile, "api_key", apiKey)
+		return nil
+	}
+
+	store, err := openCredentialStore(storeName)
+	if err != nil {
+		return err
+	}
+	if err := store.set(profile, apiKey); err != nil {
+		return err
+	}
+
+	// Remove a key saved under another reference before pointing to the new one
+	if ref, _ := config.profile(profile)["api_key_ref"].(string); ref != "" && ref != storeName+":"+profile {
+		deleteAPIKey(config, profile)
+	}
+	config.unsetProfileValue(profile, "api_key")
+	config.setProfileValue(profile, "api_key_ref", storeName+":"+profile)
+	return nil
+}
+
+// deleteAPIKey removes the API key of a profile from the config file and from the
+// credential store its reference points to. It reports whether there was a key.
+func deleteAPIKey(config *configFile, profile string) bool {
+	settings := config.profile(profile)
+	apiKey, _ := settings["api_key"].(string)
+	ref, _ := settings["api_key_ref"].(string)
+
+	if ref != "" {
+		if storeName, id, err := parseCredentialRef(ref); err == nil {
+			if store, err := openCredentialStore(storeName); err == nil {
+				if err := store.delete(id); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not remove the API key from the %s credential store: %v\n", storeName, err)
+				}
+			}
+		}
+	}
+
+	config.unsetProfileValue(profile, "api_key")
+	config.unsetProfileValue(profile, "api_key_ref")
+	return apiKey != "" || ref != ""
+}
+
+// migratePlaintextKeys moves the API keys kept in the config file by earlier versions
+// to the selected credential store. It returns the profiles that were migrated.
+func migratePlaintextKeys(config *configFile) ([]string, error) {
+	if selectedCredentialStore() == plaintextCredentialStore {
+		return nil, nil
+	}
+
+	var migrated []string
+	for _, profile := range config.profileNames() {
+		if apiKey, _ := config.profile(profile)["api_key"].(string); apiKey != "" {
+			if err := saveAPIKey(config, profile, apiKey); err != nil {
+				return migrated, err
+			}
+			migrated = append(migrated, profile)
+		}
+	}
+	return migrated, nil
+}
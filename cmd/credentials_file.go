@@ -0,0 +1,448 @@
+package cmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// defaultCredentialCacheTTL is how long an unlocked credential file stays unlocked,
+// unless the credential_cache_ttl config key sets another duration
+const defaultCredentialCacheTTL = 15 * time.Minute
+
+// credentialKDFIterations is the PBKDF2-SHA256 iteration count for new credential files
+const credentialKDFIterations = 600000
+
+// encryptedCredentials is the content of a credential file. The API keys are
+// encrypted with AES-256-GCM using a key derived from the passphrase.
+type encryptedCredentials struct {
+	Version    int    `json:"version"`    // Format version, 1
+	KDF        string `json:"kdf"`        // Key derivation function, "pbkdf2-sha256"
+	Iterations int    `json:"iterations"` // Iterations of the key derivation function
+	Salt       []byte `json:"salt"`       // Salt of the key derivation function
+	Nonce      []byte `json:"nonce"`      // AES-GCM nonce
+	Data       []byte `json:"data"`       // Encrypted JSON object of API keys by ID
+}
+
+// credentialUnlock is an unlocked credential file, cached for the session
+type credentialUnlock struct {
+	Expires time.Time `json:"expires"` // When the unlock expires
+	Salt    []byte    `json:"salt"`    // Salt of the credential file the key was derived for
+	Key     []byte    `json:"key"`     // Encryption key derived from the passphrase
+}
+
+// fileCredentialStore keeps API keys in a file encrypted with a passphrase. The file is
+// next to the config file, e.g. ~/.ics-cli.credentials for ~/.ics-cli.yaml.
+type fileCredentialStore struct {
+	path       string            // Path of the credential file
+	salt       []byte            // Salt of the key derivation, nil until the file exists
+	iterations int               // Iterations of the key derivation
+	key        []byte            // Encryption key, once unlocked
+	entries    map[string]string // API keys by ID, once unlocked
+}
+
+// openFileStore is the credential file opened by this process, so it is unlocked once
+var openFileStore *fileCredentialStore
+
+// newFileCredentialStore opens the credential file next to the config file
+func newFileCredentialStore() (credentialStore, error) {
+	configPath, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+	path := strings.TrimSuffix(configPath, filepath.Ext(configPath)) + ".credentials"
+
+	if openFileStore == nil || openFileStore.path != path {
+		openFileStore = &fileCredentialStore{path: path}
+	}
+	return openFileStore, nil
+}
+
+// get returns the API key saved under id
+func (s *fileCredentialStore) get(id string) (string, error) {
+	if err := s.unlock(); err != nil {
+		return "", err
+	}
+	apiKey, ok := s.entries[id]
+	if !ok {
+		return "", authError("no API key for %s in %s. Please run 'ics-cli auth login' to authenticate", id, s.path)
+	}
+	return apiKey, nil
+}
+
+// set saves an API key under id, creating the credential file if needed
+func (s *fileCredentialStore) set(id, apiKey string) error {
+	if err := s.unlock(); err != nil {
+		return err
+	}
+
+	if s.key == nil {
+		// New credential file
+		passphrase, err := newCredentialPassphrase(s.path)
+		if err != nil {
+			return err
+		}
+		s.salt = make([]byte, 16)
+		rand.Read(s.salt)
+		s.iterations = credentialKDFIterations
+		if s.key, err = pbkdf2.Key(sha256.New, passphrase, s.salt, s.iterations, 32); err != nil {
+			return err
+		}
+		s.cacheUnlock()
+	}
+
+	s.entries[id] = apiKey
+	return s.write()
+}
+
+// delete removes the API key saved under id
+func (s *fileCredentialStore) delete(id string) error {
+	if _, err := os.Stat(s.path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err := s.unlock(); err != nil {
+		return err
+	}
+	if _, ok := s.entries[id]; !ok {
+		return nil
+	}
+
+	delete(s.entries, id)
+	return s.write()
+}
+
+// unlock reads and decrypts the credential file, using the unlock cached for the
+// session, ICS_PASSPHRASE or a passphrase prompt. A missing file unlocks as empty.
+func (s *fileCredentialStore) unlock() error {
+	if s.entries != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.entries = make(map[string]string)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading credential file: %w", err)
+	}
+
+	var file encryptedCredentials
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("error parsing credential file %s: %w", s.path, err)
+	}
+	if file.Version != 1 || file.KDF != "pbkdf2-sha256" || file.Iterations < 1 {
+		return fmt.Errorf("unsupported credential file %s: version %d, kdf %q", s.path, file.Version, file.KDF)
+	}
+	s.salt = file.Salt
+	s.iterations = file.Iterations
+
+	// Try the unlock cached for the session first
+	cached := false
+	if s.key = s.cachedUnlock(); s.key != nil {
+		cached = true
+	} else {
+		passphrase, err := credentialPassphrase(s.path)
+		if err != nil {
+			return err
+		}
+		if s.key, err = pbkdf2.Key(sha256.New, passphrase, s.salt, s.iterations, 32); err != nil {
+			return err
+		}
+	}
+
+	plaintext, err := s.decrypt(file.Nonce, file.Data)
+	if err != nil && cached {
+		// The file was re-encrypted since, ask for the passphrase
+		s.key = nil
+		s.clearUnlock()
+		return s.unlock()
+	}
+	if err != nil {
+		s.key = nil
+		return authError("wrong passphrase for the credential file %s", s.path)
+	}
+
+	if err := json.Unmarshal(plaintext, &s.entries); err != nil {
+		return fmt.Errorf("error parsing credential file %s: %w", s.path, err)
+	}
+	if s.entries == nil {
+		s.entries = make(map[string]string)
+	}
+
+	if !cached {
+		s.cacheUnlock()
+	}
+	return nil
+}
+
+// write encrypts the API keys and writes the credential file, readable by the current user only
+func (s *fileCredentialStore) write() error {
+	plaintext, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+
+	data, err := json.MarshalIndent(encryptedCredentials{
+		Version:    1,
+		KDF:        "pbkdf2-sha256",
+		Iterations: s.iterations,
+		Salt:       s.salt,
+		Nonce:      nonce,
+		Data:       gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(s.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("error writing credential file: %w", err)
+	}
+	return nil
+}
+
+// decrypt decrypts the API keys with the unlocked key
+func (s *fileCredentialStore) decrypt(nonce, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	return gcm.Open(nil, nonce, data, nil)
+}
+
+// credentialCacheTTL returns how long an unlock is cached, 0 to not cache it
+func credentialCacheTTL() time.Duration {
+	if viper.IsSet("credential_cache_ttl") {
+		return viper.GetDuration("credential_cache_ttl")
+	}
+	return defaultCredentialCacheTTL
+}
+
+// unlockCachePrefix starts the names of the session caches of credential files
+const unlockCachePrefix = "ics-cli-unlock-"
+
+// unlockCacheDir returns the per-user directory unlocks are cached in, or "" if there is
+// none: $XDG_RUNTIME_DIR, or else an ics-cli directory in the user cache directory, such
+// as ~/Library/Caches on macOS, which is created if create is set. It must be readable by
+// the current user only: unlocks are never cached in a shared directory such as /tmp.
+func unlockCacheDir(create bool) string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" && filepath.IsAbs(dir) && isPrivateDir(dir) {
+		return dir
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Join(cacheDir, "ics-cli")
+	if create {
+		os.MkdirAll(dir, 0700)
+	}
+	if !isPrivateDir(dir) {
+		return ""
+	}
+	return dir
+}
+
+// isPrivateDir reports whether path is a directory only its owner can access
+func isPrivateDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir() && info.Mode().Perm()&0077 == 0
+}
+
+// unlockCachePath returns the path of the session cache of a credential file, or "" if
+// unlocks cannot be cached. The cache directory is created if create is set.
+func (s *fileCredentialStore) unlockCachePath(create bool) string {
+	dir := unlockCacheDir(create)
+	if dir == "" {
+		return ""
+	}
+	path, _ := filepath.Abs(s.path)
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(dir, fmt.Sprintf("%s%d-%x", unlockCachePrefix, os.Getuid(), sum[:8]))
+}
+
+// cachedUnlock returns the key cached for the session, or nil if there is none
+func (s *fileCredentialStore) cachedUnlock() []byte {
+	path := s.unlockCachePath(false)
+	if path == "" {
+		return nil
+	}
+
+	unlock, ok := readUnlockCache(path)
+	if !ok || string(unlock.Salt) != string(s.salt) {
+		s.clearUnlock()
+		return nil
+	}
+	return unlock.Key
+}
+
+// readUnlockCache reads a session cache, which is only valid until it expires
+func readUnlockCache(path string) (credentialUnlock, bool) {
+	var unlock credentialUnlock
+
+	// Only trust a cache no other user can read or have written
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0077 != 0 {
+		return unlock, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return unlock, false
+	}
+
+	if err := json.Unmarshal(data, &unlock); err != nil || time.Now().After(unlock.Expires) {
+		return unlock, false
+	}
+	return unlock, true
+}
+
+// cacheUnlock caches the key for the session, for credential_cache_ttl
+func (s *fileCredentialStore) cacheUnlock() {
+	ttl := credentialCacheTTL()
+	if ttl <= 0 {
+		return
+	}
+	path := s.unlockCachePath(true)
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "Warning: credential_cache_ttl has no effect, as there is no private directory to keep the unlock in. The passphrase is asked by every command. Set credential_cache_ttl to 0 to hide this warning")
+		return
+	}
+
+	data, err := json.Marshal(credentialUnlock{Expires: time.Now().Add(ttl), Salt: s.salt, Key: s.key})
+	if err != nil {
+		return
+	}
+
+	// Create a new file so a file planted by another user is never written to
+	os.Remove(path)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	file.Write(data)
+}
+
+// clearUnlock removes the unlock cached for the session
+func (s *fileCredentialStore) clearUnlock() {
+	if path := s.unlockCachePath(false); path != "" {
+		os.Remove(path)
+	}
+}
+
+// pruneUnlockCaches removes the session caches that have expired, so a derived key
+// does not outlive its credential_cache_ttl until the credential file is next used
+func pruneUnlockCaches() {
+	dir := unlockCacheDir(false)
+	if dir == "" {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	prefix := fmt.Sprintf("%s%d-", unlockCachePrefix, os.Getuid())
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if _, ok := readUnlockCache(path); !ok {
+			os.Remove(path)
+		}
+	}
+}
+
+// lockCredentialFile removes the session cache of the credential file, so the next
+// command asks for the passphrase again
+func lockCredentialFile() {
+	store, err := newFileCredentialStore()
+	if err != nil {
+		return
+	}
+	store.(*fileCredentialStore).clearUnlock()
+}
+
+// credentialPassphrase returns the passphrase of a credential file from ICS_PASSPHRASE or a prompt
+func credentialPassphrase(path string) (string, error) {
+	if passphrase := os.Getenv("ICS_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	return readPassphrase(fmt.Sprintf("Passphrase for %s: ", path))
+}
+
+// newCredentialPassphrase asks for the passphrase of a new credential file twice, or uses ICS_PASSPHRASE
+func newCredentialPassphrase(path string) (string, error) {
+	if passphrase := os.Getenv("ICS_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+
+	// Without a terminal to choose a passphrase, the key can only be saved in plaintext
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", usageError("cannot ask for a passphrase to encrypt %s without a terminal. Set ICS_PASSPHRASE, or set credential_store: plaintext in the config file (or ICS_CREDENTIAL_STORE=plaintext) to save the API key unencrypted", path)
+	}
+
+	fmt.Fprintf(os.Stderr, "API keys are saved encrypted in %s.\n", path)
+	passphrase, err := readPassphrase("Choose a passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", usageError("the passphrase cannot be empty")
+	}
+	confirm, err := readPassphrase("Repeat the passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != passphrase {
+		return "", usageError("the passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// readPassphrase prompts for a passphrase on the terminal without echoing it
+func readPassphrase(prompt string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", authError("the credential file is locked. Set ICS_PASSPHRASE to unlock it without a terminal")
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
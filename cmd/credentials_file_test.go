@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/term"
+)
+
+func TestFileCredentialStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".ics-cli.credentials")
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("ICS_PASSPHRASE", "correct horse")
+	if err := (&fileCredentialStore{path: path}).set("default", "secret-key"); err != nil {
+		t.Fatalf("set() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		passphrase string
+		want       string
+		code       int
+	}{
+		{name: "right passphrase", passphrase: "correct horse", want: "secret-key"},
+		{name: "wrong passphrase", passphrase: "battery staple", code: exitAuth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ICS_PASSPHRASE", tt.passphrase)
+
+			// Forget the unlock cached by earlier stores, so the passphrase is checked
+			store := &fileCredentialStore{path: path}
+			store.clearUnlock()
+
+			got, err := store.get("default")
+			if code := exitCode(err); code != tt.code {
+				t.Fatalf("get() error = %v, exit code %d, want %d", err, code, tt.code)
+			}
+			if got != tt.want {
+				t.Errorf("get() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// The file holds no plaintext key and is private to the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret-key")) {
+		t.Error("credential file contains the API key in plaintext")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("credential file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestFileCredentialStoreUnlockCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".ics-cli.credentials")
+	runtimeDir := t.TempDir()
+	if err := os.Chmod(runtimeDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("ICS_PASSPHRASE", "correct horse")
+
+	store := &fileCredentialStore{path: path}
+	if err := store.set("default", "secret-key"); err != nil {
+		t.Fatalf("set() error = %v", err)
+	}
+
+	// The cached unlock is used instead of the wrong passphrase
+	t.Setenv("ICS_PASSPHRASE", "battery staple")
+	if got, err := (&fileCredentialStore{path: path}).get("default"); err != nil || got != "secret-key" {
+		t.Fatalf("get() with a cached unlock = %q, %v", got, err)
+	}
+
+	// Once cleared, the passphrase is needed again
+	store.clearUnlock()
+	if _, err := (&fileCredentialStore{path: path}).get("default"); exitCode(err) != exitAuth {
+		t.Fatalf("get() after clearing the unlock, error = %v, want an auth error", err)
+	}
+
+	// An expired unlock is removed by the next command
+	expired, _ := json.Marshal(credentialUnlock{Expires: time.Now().Add(-time.Minute), Salt: store.salt, Key: store.key})
+	if err := os.WriteFile(store.unlockCachePath(false), expired, 0600); err != nil {
+		t.Fatal(err)
+	}
+	pruneUnlockCaches()
+	if _, err := os.Stat(store.unlockCachePath(false)); !os.IsNotExist(err) {
+		t.Errorf("expired unlock cache still exists: %v", err)
+	}
+
+	// A runtime directory other users can read is never used
+	if err := os.Chmod(runtimeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if path := store.unlockCachePath(false); filepath.Dir(path) == runtimeDir {
+		t.Errorf("unlockCachePath() = %q in a shared directory", path)
+	}
+}
+
+func TestUnlockCacheDirFallback(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	want := filepath.Join(cacheHome, "ics-cli")
+
+	// The directory is only created to cache an unlock
+	if dir := unlockCacheDir(false); dir != "" {
+		t.Errorf("unlockCacheDir(false) = %q before the directory exists, want none", dir)
+	}
+	if dir := unlockCacheDir(true); dir != want {
+		t.Fatalf("unlockCacheDir(true) = %q, want %q", dir, want)
+	}
+	if info, err := os.Stat(want); err != nil || info.Mode().Perm() != 0700 {
+		t.Fatalf("cache directory %v, %v, want mode 0700", info, err)
+	}
+
+	// A directory other users can read is never used
+	if err := os.Chmod(want, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if dir := unlockCacheDir(true); dir != "" {
+		t.Errorf("unlockCacheDir() = %q with mode 0755, want none", dir)
+	}
+}
+
+func TestNewCredentialPassphraseWithoutTerminal(t *testing.T) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		t.Skip("stdin is a terminal")
+	}
+	t.Setenv("ICS_PASSPHRASE", "")
+
+	if _, err := newCredentialPassphrase("test.credentials"); exitCode(err) != exitUsage {
+		t.Errorf("newCredentialPassphrase() error = %v, want a usage error", err)
+	}
+}
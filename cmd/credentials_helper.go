@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// credentialHelperExpiryMargin is how long before it expires a key is fetched again,
+// so it does not expire during a request
+const credentialHelperExpiryMargin = 30 * time.Second
+
+// credentialHelperRequest is written as JSON to the stdin of a credential helper
+type credentialHelperRequest struct {
+	Profile  string `json:"profile"`            // Profile the key is for
+	APIURL   string `json:"api_url,omitempty"`  // API URL the key is for, empty for the default URL
+	Rejected bool   `json:"rejected,omitempty"` // Whether the API rejected the key the helper returned last
+}
+
+// credentialHelperResponse is read as JSON from the stdout of a credential helper
+type credentialHelperResponse struct {
+	APIKey    string    `json:"api_key"`              // API key
+	ExpiresAt time.Time `json:"expires_at,omitempty"` // When the key expires, in RFC 3339 format, optional
+}
+
+// credentialHelper gets API keys from an external command set with the credential_helper
+// config key, modelled on git credential helpers. The command is run through the shell
+// with the "get" argument. Keys are cached for the process until they expire or the
+// API rejects them.
+type credentialHelper struct {
+	command string // Shell command of the helper
+	profile string // Profile the keys are for
+	apiURL  string // API URL the keys are for
+
+	mu      sync.Mutex
+	apiKey  string    // Key returned last
+	expires time.Time // When the key expires, zero if it does not
+}
+
+// newCredentialHelper returns the credential helper configured for the active profile, or nil
+func newCredentialHelper() *credentialHelper {
+	command := viper.GetString("credential_helper")
+	if command == "" {
+		return nil
+	}
+	return &credentialHelper{command: command, profile: activeProfile, apiURL: viper.GetString("api_url")}
+}
+
+// APIKey returns the cached key, or runs the helper if there is none, it expires soon
+// or the API rejected it. It implements icsapi.CredentialSource.
+func (h *credentialHelper) APIKey(ctx context.Context, rejected string) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fresh := h.expires.IsZero() || time.Now().Add(credentialHelperExpiryMargin).Before(h.expires)
+	if h.apiKey != "" && fresh && (rejected == "" || rejected != h.apiKey) {
+		return h.apiKey, nil
+	}
+
+	response, err := h.run(ctx, rejected != "")
+	if err != nil {
+		return "", err
+	}
+	h.apiKey, h.expires = response.APIKey, response.ExpiresAt
+	return h.apiKey, nil
+}
+
+// run runs the helper. Its stderr is passed through so it can prompt the user.
+func (h *credentialHelper) run(ctx context.Context, rejected bool) (*credentialHelperResponse, error) {
+	input, err := json.Marshal(credentialHelperRequest{Profile: h.profile, APIURL: h.apiURL, Rejected: rejected})
+	if err != nil {
+		return nil, err
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", h.command+" get")
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", h.command+" get")
+	}
+	var stdout bytes.Buffer
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "ICS_PROFILE="+h.profile)
+
+	if err := cmd.Run(); err != nil {
+		return nil, authError("credential helper %q failed: %v", h.command, err)
+	}
+
+	var response credentialHelperResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, authError("credential helper %q returned invalid JSON: %v", h.command, err)
+	}
+	if response.APIKey == "" {
+		return nil, authError("credential helper %q returned no api_key", h.command)
+	}
+	return &response, nil
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// devCmd represents the dev command
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Tools for developing and testing against the Ingenuity Cloud Services API",
+}
+
+func init() {
+	rootCmd.AddCommand(devCmd)
+}
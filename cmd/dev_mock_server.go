@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/UK2Group/ics-cli/icsapi/icsapitest"
+	"github.com/spf13/cobra"
+)
+
+// devMockServerCmd represents the dev mock-server command
+var devMockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "Run a fake Ingenuity Cloud Services API for offline development and CI",
+	Long: `Run an in-memory fake of the Ingenuity Cloud Services API.
+
+The fake serves the endpoints used by this CLI and keeps a fleet whose state changes
+on power, reinstall, order and SSH key operations. State is lost when it stops.
+
+The fleet is seeded from a built-in example, or from a YAML or JSON file with --seed.
+Point the CLI at it with --api-url or ICS_API_URL. Any non-empty API key is accepted
+unless --api-key is set.`,
+	Example: `  # Start the mock server with the built-in fleet
+  ics-cli dev mock-server --listen 127.0.0.1:8080
+
+  # Use it from another shell, keeping the test key in plaintext
+  ICS_API_URL=http://127.0.0.1:8080 ICS_CREDENTIAL_STORE=plaintext ics-cli auth login --key test
+  ICS_API_URL=http://127.0.0.1:8080 ics-cli baremetal list
+
+  # Seed the fleet from a file and simulate slow provisioning
+  ics-cli dev mock-server --seed fleet.yaml --provision-delay 2m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		seedFile, _ := cmd.Flags().GetString("seed")
+		apiKey, _ := cmd.Flags().GetString("api-key")
+		powerDelay, _ := cmd.Flags().GetDuration("power-delay")
+		provisionDelay, _ := cmd.Flags().GetDuration("provision-delay")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+
+		// Load the seed fleet
+		seed := icsapitest.DefaultSeed()
+		if seedFile != "" {
+			var err error
+			seed, err = icsapitest.LoadSeed(seedFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		var handler http.Handler = icsapitest.NewServer(seed, icsapitest.Options{
+			APIKey:         apiKey,
+			PowerDelay:     powerDelay,
+			ProvisionDelay: provisionDelay,
+		})
+		if !quiet {
+			handler = logRequests(handler)
+		}
+
+		listener, err := net.Listen("tcp", listen)
+		if err != nil {
+			return fmt.Errorf("failed to start mock server: %w", err)
+		}
+
+		fmt.Printf("%s %s\n", BlueHeading("Mock ICS API listening on:"), WhiteText("http://"+listener.Addr().String()))
+		fmt.Printf("%s %d servers, %d SSH keys, %d inventory entries\n", BlueHeading("Seeded with:"), len(seed.Servers), len(seed.SSHKeys), len(seed.Inventory))
+
+		server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			<-cmd.Context().Done()
+			server.Close()
+		}()
+
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("failed to serve mock API: %w", err)
+		}
+		return nil
+	},
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs each request handled by the mock server to stderr
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		fmt.Fprintf(os.Stderr, "%s %s %s %d\n", time.Now().Format("15:04:05"), r.Method, r.URL.RequestURI(), recorder.status)
+	})
+}
+
+func init() {
+	devCmd.AddCommand(devMockServerCmd)
+
+	devMockServerCmd.Flags().String("listen", "127.0.0.1:8080", "Address to listen on")
+	devMockServerCmd.Flags().String("seed", "", "YAML or JSON file describing the initial fleet")
+	devMockServerCmd.Flags().String("api-key", "", "Only accept this API key (default accepts any key)")
+	devMockServerCmd.Flags().Duration("power-delay", 5*time.Second, "Time a power transition takes to complete")
+	devMockServerCmd.Flags().Duration("provision-delay", 30*time.Second, "Time a reinstall or new order takes to provision")
+	devMockServerCmd.Flags().BoolP("quiet", "q", false, "Don't log requests")
+}
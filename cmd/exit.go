@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+)
+
+// Exit codes of ics-cli. They are part of the CLI's interface: scripts branch on them,
+// so existing values must not change.
+const (
+	exitOK       = 0 // Success
+	exitFailure  = 1 // Unexpected error, e.g. a network failure or a server error
+	exitUsage    = 2 // Invalid command, arguments or flags
+	exitAuth     = 3 // Not logged in, or the API key was rejected
+	exitNotFound = 4 // Server, SSH key or other resource not found
+	exitRejected = 5 // The API refused the request, or reported that the action failed
+	exitPartial  = 6 // Some items of a bulk operation failed
+	exitAborted  = 7 // The user declined a confirmation prompt
+	exitTimeout  = 8 // A wait did not complete before its timeout
+)
+
+// exitError is an error carrying the exit code ics-cli should return
+type exitError struct {
+	code   int
+	err    error
+	silent bool // Whether the error was already reported to the user
+}
+
+// Error implements the error interface
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// usageError returns an error for invalid arguments or flags
+func usageError(format string, a ...interface{}) error {
+	return &exitError{code: exitUsage, err: fmt.Errorf(format, a...)}
+}
+
+// authError returns an error for a missing or rejected API key
+func authError(format string, a ...interface{}) error {
+	return &exitError{code: exitAuth, err: fmt.Errorf(format, a...)}
+}
+
+// rejectedError returns an error for an action the API reported as failed
+func rejectedError(format string, a ...interface{}) error {
+	return &exitError{code: exitRejected, err: fmt.Errorf(format, a...)}
+}
+
+// abortedError returns an error for a confirmation prompt the user declined
+func abortedError(format string, a ...interface{}) error {
+	return &exitError{code: exitAborted, err: fmt.Errorf(format, a...)}
+}
+
+// exitCode maps an error returned by a command to an exit code
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	// A server query matching several servers needs a more precise argument
+	var ambiguous *icsapi.AmbiguousServerError
+	if errors.As(err, &ambiguous) || errors.Is(err, path.ErrBadPattern) {
+		return exitUsage
+	}
+
+	if errors.Is(err, icsapi.ErrWaitTimeout) {
+		return exitTimeout
+	}
+
+	if errors.Is(err, icsapi.ErrMissingAPIKey) || errors.Is(err, icsapi.ErrUnauthorized) {
+		return exitAuth
+	}
+	if errors.Is(err, icsapi.ErrNotFound) {
+		return exitNotFound
+	}
+
+	var apiErr *icsapi.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusForbidden:
+			return exitAuth
+		case apiErr.StatusCode >= 400 && apiErr.StatusCode < 500:
+			return exitRejected
+		}
+	}
+
+	return exitFailure
+}
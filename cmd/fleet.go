@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// fleetCmd represents the fleet command
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "Manage Baremetal Servers from a declarative fleet spec",
+	Long: `Describe the servers you want in a YAML file and let ics-cli work out the changes.
+
+  servers:
+    - name: web-01            # Friendly name, identifies the server
+      sku: c1i.small          # Server type, used to order the server
+      datacenter: NYC1
+      os: UBUNTU_24_04        # Operating system, reinstalled if it differs
+      ssh_keys: [deploy]      # Labels of the SSH keys to assign
+      pxe_url: https://example.com/boot.ipxe   # Optional, set once a new server is provisioned
+
+A server in the spec is matched to an existing server by service_id or hostname if given,
+otherwise by friendly name. Servers with no match are ordered. Servers in the account that
+are not in the spec are left alone.`,
+}
+
+func init() {
+	rootCmd.AddCommand(fleetCmd)
+}
+
+// fleetSpec is a fleet spec file
+type fleetSpec struct {
+	Servers []fleetServer `yaml:"servers"` // Servers in the fleet
+}
+
+// fleetServer is a server in a fleet spec
+type fleetServer struct {
+	Name       string   `yaml:"name"`       // Friendly name of the server
+	ServiceID  int      `yaml:"service_id"` // Service ID of an existing server to manage, optional
+	Hostname   string   `yaml:"hostname"`   // Hostname of an existing server to manage, optional
+	SKU        string   `yaml:"sku"`        // Server type to order
+	Datacenter string   `yaml:"datacenter"` // Datacenter location, e.g. NYC1
+	OS         string   `yaml:"os"`         // Operating system product code or image ID
+	SSHKeys    []string `yaml:"ssh_keys"`   // Labels of the SSH keys assigned to the server
+	PXEURL     string   `yaml:"pxe_url"`    // Custom PXE boot URL set on new servers, optional
+}
+
+// loadFleetSpec reads and checks a fleet spec file. A path of "-" reads stdin.
+func loadFleetSpec(path string) (*fleetSpec, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading fleet spec: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	// Reject unknown fields, which are most likely typos
+	var spec fleetSpec
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return nil, usageError("error parsing fleet spec: %v", err)
+	}
+
+	var problems []string
+	names := make(map[string]bool)
+	for i, server := range spec.Servers {
+		if server.Name == "" {
+			problems = append(problems, fmt.Sprintf("server %d: name is required", i+1))
+			continue
+		}
+		if names[strings.ToLower(server.Name)] {
+			problems = append(problems, fmt.Sprintf("%s: name is used by more than one server", server.Name))
+		}
+		names[strings.ToLower(server.Name)] = true
+
+		if server.Datacenter == "" {
+			problems = append(problems, fmt.Sprintf("%s: datacenter is required", server.Name))
+		}
+		if server.OS == "" {
+			problems = append(problems, fmt.Sprintf("%s: os is required", server.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, usageError("invalid fleet spec:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return &spec, nil
+}
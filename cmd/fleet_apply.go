@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// fleetApplyCmd represents the fleet apply command
+var fleetApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Make the fleet match a spec",
+	Long: `Compare a fleet spec with the servers in the account, show the changes needed and apply
+them once confirmed.
+
+Existing servers are renamed, given their SSH keys and reinstalled first. Missing servers
+are then ordered; they are named once they appear in the server list and, if the spec sets
+a PXE URL, given it once provisioned. Both waits are bounded by --timeout.`,
+	Example: `  # Apply a fleet spec
+  ics-cli fleet apply -f fleet.yaml
+
+  # Apply without prompting, e.g. from CI
+  ics-cli fleet apply -f fleet.yaml -d`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		spec, err := loadFleetSpec(path)
+		if err != nil {
+			return err
+		}
+
+		timeout, interval, err := getPollFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+
+		changes, err := planFleet(cmd.Context(), client, spec)
+		if err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			if !isTableOutput() {
+				return printStructured([]fleetResult{})
+			}
+			fmt.Println(GreenText("No changes. The fleet matches the spec."))
+			return nil
+		}
+
+		// Check if user wants to proceed
+		dontPrompt, _ := cmd.Flags().GetBool("dont")
+		if !dontPrompt && !confirmFleetPlan(changes) {
+			return abortedError("aborted by user")
+		}
+
+		results := applyFleet(cmd, client, changes, timeout, interval)
+		return printFleetResults(results)
+	},
+}
+
+func init() {
+	fleetCmd.AddCommand(fleetApplyCmd)
+
+	fleetApplyCmd.Flags().StringP("file", "f", "", "Fleet spec file, or - to read stdin")
+	fleetApplyCmd.Flags().BoolP("dont", "d", false, "Don't prompt for confirmation")
+	addPollFlags(fleetApplyCmd, defaultProvisionTimeout)
+	fleetApplyCmd.MarkFlagRequired("file")
+}
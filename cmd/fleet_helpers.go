@@ -0,0 +1,552 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+)
+
+// fleetChange is a change needed to make the fleet match its spec
+type fleetChange struct {
+	Action      string `json:"action"`               // "order", "rename", "ssh-keys" or "reinstall"
+	Name        string `json:"name"`                 // Friendly name of the server in the spec
+	ServiceID   int    `json:"service_id,omitempty"` // Service ID of the server, unset for an order not yet placed
+	Description string `json:"description"`          // What the change does
+
+	spec    *fleetServer        // Server in the spec
+	server  icsapi.Server       // Existing server, unless ordering
+	order   icsapi.OrderRequest // Order to place, for "order"
+	keyIDs  []int               // SSH keys to assign, for "ssh-keys"
+	imageID string              // Operating system image to install, for "reinstall"
+}
+
+// fleetResult is the outcome of applying a fleet change
+type fleetResult struct {
+	fleetChange
+	Success bool   `json:"success"` // Whether the change was applied
+	Message string `json:"message"` // Outcome of the change
+}
+
+// planFleet compares a fleet spec with the servers in the account and returns the changes
+// needed, in the order they should be applied. Every problem preventing the spec from being
+// applied is reported in a single error.
+func planFleet(ctx context.Context, client *icsapi.Client, spec *fleetSpec) ([]fleetChange, error) {
+	servers, err := client.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := client.ListSSHKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyIDs := make(map[string]int, len(keys))
+	for _, key := range keys {
+		keyIDs[key.Label] = key.ID
+	}
+
+	var changes []fleetChange
+	var problems []string
+	claimed := make(map[string]string) // Spec name of each matched server ID
+
+	for i := range spec.Servers {
+		want := &spec.Servers[i]
+
+		var ids []int
+		for _, label := range want.SSHKeys {
+			id, ok := keyIDs[label]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: SSH key %q not found", want.Name, label))
+				continue
+			}
+			ids = append(ids, id)
+		}
+
+		server, err := matchFleetServer(servers, want)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", want.Name, err))
+			continue
+		}
+
+		// Servers with no match are ordered
+		if server == nil {
+			if want.SKU == "" {
+				problems = append(problems, fmt.Sprintf("%s: no existing server matches, and sku is required to order one", want.Name))
+				continue
+			}
+
+			description := fmt.Sprintf("order %s in %s with %s", want.SKU, want.Datacenter, want.OS)
+			if len(want.SSHKeys) > 0 {
+				description += fmt.Sprintf(" and SSH keys %s", strings.Join(want.SSHKeys, ", "))
+			}
+			changes = append(changes, fleetChange{
+				Action:      "order",
+				Name:        want.Name,
+				Description: description,
+				spec:        want,
+				order: icsapi.OrderRequest{
+					SKUProductName:             want.SKU,
+					Quantity:                   1,
+					LocationCode:               want.Datacenter,
+					OperatingSystemProductCode: want.OS,
+					SSHKeyIDs:                  ids,
+				},
+			})
+			continue
+		}
+
+		if other, ok := claimed[server.ID]; ok {
+			problems = append(problems, fmt.Sprintf("%s: matches the same server as %s (%d)", want.Name, other, server.ServiceID))
+			continue
+		}
+		claimed[server.ID] = want.Name
+
+		serverChanges, serverProblems, err := planFleetServer(ctx, client, want, *server, keyIDs)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, serverChanges...)
+		problems = append(problems, serverProblems...)
+	}
+
+	orderProblems, err := checkFleetOrders(ctx, client, changes)
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, orderProblems...)
+
+	if len(problems) > 0 {
+		return nil, rejectedError("the fleet spec cannot be applied:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return changes, nil
+}
+
+// matchFleetServer returns the existing server a spec server describes, or nil if there is none
+func matchFleetServer(servers []icsapi.Server, want *fleetServer) (*icsapi.Server, error) {
+	var matches []icsapi.Server
+	for _, server := range servers {
+		switch {
+		case want.ServiceID != 0:
+			if server.ServiceID == want.ServiceID {
+				matches = append(matches, server)
+			}
+		case want.Hostname != "":
+			if strings.EqualFold(server.Hostname, want.Hostname) {
+				matches = append(matches, server)
+			}
+		default:
+			if strings.EqualFold(server.FriendlyName, want.Name) {
+				matches = append(matches, server)
+			}
+		}
+	}
+
+	switch {
+	case len(matches) > 1:
+		return nil, &icsapi.AmbiguousServerError{Query: want.Name, Candidates: matches}
+	case len(matches) == 1:
+		return &matches[0], nil
+	case want.ServiceID != 0:
+		return nil, fmt.Errorf("no server with service ID %d", want.ServiceID)
+	case want.Hostname != "":
+		return nil, fmt.Errorf("no server with hostname %s", want.Hostname)
+	default:
+		return nil, nil
+	}
+}
+
+// planFleetServer returns the changes needed to make an existing server match its spec,
+// and the problems preventing it
+func planFleetServer(ctx context.Context, client *icsapi.Client, want *fleetServer, server icsapi.Server, keyIDs map[string]int) ([]fleetChange, []string, error) {
+	detail, err := client.GetServer(ctx, server.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []fleetChange
+	var problems []string
+	change := fleetChange{Name: want.Name, ServiceID: server.ServiceID, spec: want, server: server}
+
+	if !strings.EqualFold(detail.DatacenterName, want.Datacenter) {
+		problems = append(problems, fmt.Sprintf("%s: server %d is in %s, not %s. Servers cannot be moved", want.Name, server.ServiceID, detail.DatacenterName, want.Datacenter))
+	}
+
+	if detail.FriendlyName != want.Name {
+		rename := change
+		rename.Action = "rename"
+		rename.Description = fmt.Sprintf("rename %s from %q", server.Hostname, detail.FriendlyName)
+		changes = append(changes, rename)
+	}
+
+	// Keys are assigned before a reinstall, which installs them
+	assigned, err := client.GetServerSSHKeys(ctx, server.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var added, removed []string
+	var current, desired []int
+	for _, key := range assigned {
+		current = append(current, key.ID)
+		if !slices.Contains(want.SSHKeys, key.Label) {
+			removed = append(removed, key.Label)
+		}
+	}
+	for _, label := range want.SSHKeys {
+		id, ok := keyIDs[label]
+		if !ok {
+			continue
+		}
+		desired = append(desired, id)
+		if !slices.Contains(current, id) {
+			added = append(added, label)
+		}
+	}
+	if len(added) > 0 || len(removed) > 0 {
+		var parts []string
+		for _, label := range added {
+			parts = append(parts, "+"+label)
+		}
+		for _, label := range removed {
+			parts = append(parts, "-"+label)
+		}
+		assign := change
+		assign.Action = "ssh-keys"
+		assign.Description = fmt.Sprintf("set SSH keys (%s), installed on the next reinstall", strings.Join(parts, " "))
+		assign.keyIDs = desired
+		changes = append(changes, assign)
+	}
+
+	if !sameOS(want.OS, detail.OperatingSystemID) && !sameOS(want.OS, detail.OperatingSystemName) {
+		images, err := client.ListOS(ctx, server.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var image *icsapi.OS
+		for i := range images {
+			if sameOS(want.OS, images[i].ID) || sameOS(want.OS, images[i].Name) {
+				image = &images[i]
+				break
+			}
+		}
+
+		if image == nil {
+			problems = append(problems, fmt.Sprintf("%s: operating system %q is not available to reinstall server %d", want.Name, want.OS, server.ServiceID))
+		} else {
+			reinstall := change
+			reinstall.Action = "reinstall"
+			reinstall.Description = fmt.Sprintf("reinstall with %s, replacing %s. All data on the server is erased", image.Name, detail.OperatingSystemName)
+			reinstall.imageID = image.ID
+			changes = append(changes, reinstall)
+		}
+	}
+
+	return changes, problems, nil
+}
+
+// checkFleetOrders checks the orders of a plan against the inventory and add-ons, with
+// enough stock for all the servers ordered of each SKU and location
+func checkFleetOrders(ctx context.Context, client *icsapi.Client, changes []fleetChange) ([]string, error) {
+	quantities := make(map[string]int)
+	for _, change := range changes {
+		if change.Action == "order" {
+			quantities[orderKey(change.order)]++
+		}
+	}
+
+	var problems []string
+	catalogs := make(map[string]*icsapi.OrderCatalog)
+	seen := make(map[string]bool)
+
+	for _, change := range changes {
+		if change.Action != "order" {
+			continue
+		}
+
+		key := orderKey(change.order)
+		catalog, ok := catalogs[key]
+		if !ok {
+			var err error
+			catalog, err = client.GetOrderCatalog(ctx, change.order)
+			if err != nil {
+				return nil, fmt.Errorf("failed to run pre-flight checks: %w", err)
+			}
+			catalogs[key] = catalog
+		}
+
+		// Report a problem shared by several orders, such as missing stock, once
+		order := change.order
+		order.Quantity = quantities[key]
+		for _, problem := range catalog.Validate(order) {
+			if !seen[problem] {
+				seen[problem] = true
+				problems = append(problems, fmt.Sprintf("%s: %s", change.Name, problem))
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// orderKey identifies the SKU and location of an order
+func orderKey(order icsapi.OrderRequest) string {
+	return strings.ToLower(order.SKUProductName + "/" + order.LocationCode)
+}
+
+// sameOS reports whether two names refer to the same operating system, so that a product
+// code such as UBUNTU_24_04 matches the image ID ubuntu-24-04 or the name "Ubuntu 24.04"
+func sameOS(a, b string) bool {
+	normalize := func(name string) string {
+		return strings.Map(func(r rune) rune {
+			switch r {
+			case '_', ' ', '.':
+				return '-'
+			}
+			return unicode.ToLower(r)
+		}, name)
+	}
+	return a != "" && normalize(a) == normalize(b)
+}
+
+// printFleetPlan prints the changes of a plan and a summary
+func printFleetPlan(w io.Writer, changes []fleetChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, GreenText("No changes. The fleet matches the spec."))
+		return
+	}
+
+	orders, updates, reinstalls := 0, 0, 0
+	for _, change := range changes {
+		target := change.Name
+		if change.ServiceID != 0 {
+			target = fmt.Sprintf("%s (%d)", change.Name, change.ServiceID)
+		}
+
+		switch change.Action {
+		case "order":
+			orders++
+			fmt.Fprintf(w, "%s %s\n", GreenText(fmt.Sprintf("+ %-9s %s:", change.Action, target)), change.Description)
+		case "reinstall":
+			reinstalls++
+			fmt.Fprintf(w, "%s %s\n", RedText(fmt.Sprintf("! %-9s %s:", change.Action, target)), change.Description)
+		default:
+			updates++
+			fmt.Fprintf(w, "%s %s\n", YellowText(fmt.Sprintf("~ %-9s %s:", change.Action, target)), change.Description)
+		}
+	}
+
+	fmt.Fprintln(w, BlueHeading(fmt.Sprintf("\nPlan: %d to order, %d to update, %d to reinstall.", orders, updates, reinstalls)))
+}
+
+// applyFleet applies the changes of a plan. Changes to existing servers are made first,
+// then the orders are placed. Ordered servers are named once they appear in the server
+// list, and given their PXE URL once provisioned, within the timeout.
+func applyFleet(cmd *cobra.Command, client *icsapi.Client, changes []fleetChange, timeout, interval time.Duration) []fleetResult {
+	ctx := cmd.Context()
+	results := make([]fleetResult, len(changes))
+	failed := make(map[string]bool) // Servers with a failed change
+
+	for i, change := range changes {
+		results[i].fleetChange = change
+		if change.Action == "order" {
+			continue
+		}
+
+		// A reinstall after a failed key assignment would install the wrong keys
+		if failed[change.server.ID] {
+			results[i].Message = "Skipped, as an earlier change to the server failed."
+			continue
+		}
+
+		var success bool
+		var err error
+		var message string
+		switch change.Action {
+		case "rename":
+			err = client.SetFriendlyName(ctx, change.server.ID, change.Name)
+			success, message = err == nil, "Renamed the server."
+		case "ssh-keys":
+			success, err = client.AssignSSHKeys(ctx, change.server.ServiceID, change.keyIDs)
+			message = "Assigned the SSH keys."
+		case "reinstall":
+			success, err = client.ReinstallOS(ctx, change.server.ID, change.imageID, "Applied from fleet spec")
+			message = "Started the reinstall."
+		}
+
+		switch {
+		case err != nil:
+			results[i].Message = err.Error()
+		case !success:
+			results[i].Message = fmt.Sprintf("The API reported the %s failed.", change.Action)
+		default:
+			results[i].Success = true
+			results[i].Message = message
+		}
+		if !results[i].Success {
+			failed[change.server.ID] = true
+		}
+	}
+
+	// Place the orders, then finish setting up the new servers together
+	var placed []int
+	var serviceIDs []int
+	for i, change := range changes {
+		if change.Action != "order" {
+			continue
+		}
+
+		ids, err := client.PlaceOrder(ctx, change.order)
+		if err != nil || len(ids) == 0 {
+			results[i].Message = fmt.Sprintf("The order was not placed: %v", err)
+			continue
+		}
+		results[i].ServiceID = ids[0]
+		results[i].Message = fmt.Sprintf("Ordered as service %d.", ids[0])
+		placed = append(placed, i)
+		serviceIDs = append(serviceIDs, ids[0])
+	}
+
+	if len(placed) == 0 {
+		return results
+	}
+	deadline := time.Now().Add(timeout)
+
+	waitCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	status := newProgress(fmt.Sprintf("Waiting for %d ordered server(s) to be created...", len(placed)))
+	servers, err := client.WaitForServices(waitCtx, serviceIDs, interval)
+	status.Stop()
+	if err != nil {
+		for _, i := range placed {
+			results[i].Message += fmt.Sprintf(" Failed to name the server: %v", err)
+		}
+		return results
+	}
+
+	var pxe []int
+	var pxeServers []icsapi.Server
+	for j, i := range placed {
+		results[i].server = servers[j]
+		if err := client.SetFriendlyName(ctx, servers[j].ID, results[i].Name); err != nil {
+			results[i].Message += fmt.Sprintf(" Failed to name the server: %v", err)
+			continue
+		}
+
+		if results[i].spec.PXEURL == "" {
+			results[i].Success = true
+			continue
+		}
+		pxe = append(pxe, i)
+		pxeServers = append(pxeServers, servers[j])
+	}
+
+	if len(pxe) == 0 {
+		return results
+	}
+
+	// A custom PXE URL reboots the server, so it is set once provisioning is complete
+	states, err := waitForServers(ctx, client, pxeServers, waitConditions["provisioned"], defaultParallel, time.Until(deadline), interval)
+	for j, i := range pxe {
+		switch {
+		case err != nil:
+			results[i].Message += fmt.Sprintf(" Failed to set the PXE URL: %v", err)
+		case states[j].err != nil:
+			results[i].Message += fmt.Sprintf(" Failed to set the PXE URL: %v", states[j].err)
+		default:
+			if err := client.SetPXEURL(ctx, pxeServers[j].ID, results[i].spec.PXEURL); err != nil {
+				results[i].Message += fmt.Sprintf(" Failed to set the PXE URL: %v", err)
+				continue
+			}
+			results[i].Success = true
+			results[i].Message += " PXE URL set."
+		}
+	}
+
+	return results
+}
+
+// printFleetResults prints the outcome of applying a plan. It returns an error with the
+// exitPartial code if some changes failed, or exitFailure if they all did.
+func printFleetResults(results []fleetResult) error {
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+
+	if !isTableOutput() {
+		if err := printStructured(results); err != nil {
+			return err
+		}
+	} else {
+		headerFmt := color.New(color.FgBlue).SprintfFunc()
+		columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+		tbl := table.New("Action", "Name", "Service ID", "Result", "Message")
+		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+		for _, result := range results {
+			status := GreenText("OK")
+			if !result.Success {
+				status = RedText("FAILED")
+			}
+			serviceID := ""
+			if result.ServiceID != 0 {
+				serviceID = strconv.Itoa(result.ServiceID)
+			}
+			tbl.AddRow(result.Action, result.Name, serviceID, status, result.Message)
+		}
+
+		tbl.Print()
+
+		summary := fmt.Sprintf("\n%d of %d changes applied", len(results)-failed, len(results))
+		if failed == 0 {
+			fmt.Println(BlueHeading(summary))
+		} else {
+			fmt.Println(RedText(summary))
+		}
+	}
+
+	switch {
+	case failed == 0:
+		return nil
+	case failed == len(results):
+		return &exitError{code: exitFailure, err: fmt.Errorf("every change failed"), silent: true}
+	default:
+		return &exitError{code: exitPartial, err: fmt.Errorf("%d of %d changes failed", failed, len(results)), silent: true}
+	}
+}
+
+// confirmFleetPlan shows a plan on stderr and asks the user to apply it
+func confirmFleetPlan(changes []fleetChange) bool {
+	printFleetPlan(os.Stderr, changes)
+
+	reinstalls := 0
+	for _, change := range changes {
+		if change.Action == "reinstall" {
+			reinstalls++
+		}
+	}
+	if reinstalls > 0 {
+		fmt.Fprintln(os.Stderr, RedText(fmt.Sprintf("%d server(s) will be reinstalled, erasing all data on them.", reinstalls)))
+	}
+
+	fmt.Fprint(os.Stderr, "Apply these changes? (y/n): ")
+	var response string
+	fmt.Scanln(&response)
+	return response == "y"
+}
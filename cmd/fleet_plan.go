@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// fleetPlanCmd represents the fleet plan command
+var fleetPlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show the changes needed to make the fleet match a spec",
+	Long: `Compare a fleet spec with the servers in the account and show the orders, renames,
+SSH key assignments and reinstalls needed to make them match. Nothing is changed.`,
+	Example: `  # Show the changes needed
+  ics-cli fleet plan -f fleet.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		spec, err := loadFleetSpec(path)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+
+		changes, err := planFleet(cmd.Context(), client, spec)
+		if err != nil {
+			return err
+		}
+
+		if !isTableOutput() {
+			if changes == nil {
+				changes = []fleetChange{}
+			}
+			return printStructured(changes)
+		}
+
+		printFleetPlan(os.Stdout, changes)
+		return nil
+	},
+}
+
+func init() {
+	fleetCmd.AddCommand(fleetPlanCmd)
+
+	fleetPlanCmd.Flags().StringP("file", "f", "", "Fleet spec file, or - to read stdin")
+	fleetPlanCmd.MarkFlagRequired("file")
+}
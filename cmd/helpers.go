@@ -1,66 +1,52 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os/exec"
 	"runtime"
-	"time"
+	"strings"
 
+	"github.com/UK2Group/ics-cli/icsapi"
 	"github.com/fatih/color"
 	"github.com/spf13/viper"
 )
 
-// makeAPIRequest is a generic function to handle API calls with proper error handling
-func makeAPIRequest(method string, timeout time.Duration, url string, body io.Reader, result interface{}) error {
-
-	client := &http.Client{Timeout: timeout * time.Second}
-
-	// Check if API key exists in configuration
-	apiKey := viper.GetString("api_key")
-	if apiKey == "" {
-		return fmt.Errorf("not logged in. Please run 'ics-cli auth login' to authenticate")
-	}
-
-	// Make API call
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
+// newAPIClient returns an API client authenticated with the configured API key, or with
+// keys from the credential helper if one is configured and no key is set explicitly
+func newAPIClient() (*icsapi.Client, error) {
+	if helper := newCredentialHelper(); helper != nil && viper.GetString("api_key") == "" {
+		showActiveProfile()
+		client := newAPIClientWithKey("")
+		client.Credentials = helper
+		return client, nil
 	}
 
-	req.Header.Add("X-Api-Token", apiKey)
-
-	resp, err := client.Do(req)
+	// Check if API key exists in ICS_API_KEY or the configuration
+	apiKey, err := configuredAPIKey()
 	if err != nil {
-		return fmt.Errorf("error connecting to API: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	// Handle status codes
-	switch resp.StatusCode {
-	case http.StatusUnauthorized:
-		return fmt.Errorf("API key is invalid or expired")
-	case http.StatusNotFound:
-		return fmt.Errorf("resource not found: %s", url)
+	if apiKey == "" {
+		if activeProfile != defaultProfile {
+			return nil, authError("not logged in to profile %s. Please run 'ics-cli auth login --profile %s' to authenticate", activeProfile, activeProfile)
+		}
+		return nil, authError("not logged in. Please run 'ics-cli auth login' to authenticate")
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API returned error status code: %d", resp.StatusCode)
-	}
+	showActiveProfile()
 
-	// Read and parse the response
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("error reading API response: %w", err)
-	}
+	return newAPIClientWithKey(apiKey), nil
+}
 
-	if err := json.Unmarshal(respBody, result); err != nil {
-		return fmt.Errorf("error parsing API response: %w", err)
+// newAPIClientWithKey returns an API client for the configured API URL using the given API key
+func newAPIClientWithKey(apiKey string) *icsapi.Client {
+	client := icsapi.NewClient(apiKey)
+	if apiURL := viper.GetString("api_url"); apiURL != "" {
+		client.BaseURL = strings.TrimSuffix(apiURL, "/")
 	}
+	client.Retry.MaxRetries = viper.GetInt("retries")
 
-	return nil
+	return client
 }
 
 // openBrowser opens a URL in the default browser
@@ -0,0 +1,624 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPathNode is an element of a parsed JSONPath template, in the style of kubectl:
+// literal text, a {path} to print, or a {range path}...{end} block
+type jsonPathNode struct {
+	text    string          // Literal text, printed as is
+	path    []jsonPathStep  // Path evaluated against the current value
+	isPath  bool            // Whether the node prints path
+	isRange bool            // Whether the node repeats body for each match of path
+	body    []*jsonPathNode // Nodes of a range block
+}
+
+// jsonPathStepKind identifies the type of a path step
+type jsonPathStepKind int
+
+const (
+	stepField     jsonPathStepKind = iota // .name or ['name']
+	stepRecursive                         // ..name
+	stepWildcard                          // .* or [*]
+	stepIndex                             // [n]
+	stepSlice                             // [start:end]
+	stepFilter                            // [?(@.name == value)]
+)
+
+// jsonPathStep is a single step of a JSONPath expression
+type jsonPathStep struct {
+	kind       jsonPathStepKind
+	name       string         // Key for field and recursive steps
+	index      int            // Index for index steps, start for slice steps
+	end        int            // End of a slice step
+	hasStart   bool           // Whether a slice step has a start
+	hasEnd     bool           // Whether a slice step has an end
+	filterPath []jsonPathStep // Path compared by a filter step, relative to @
+	filterOp   string         // Comparison operator of a filter step, empty to test for existence
+	filterArg  interface{}    // Literal compared against by a filter step
+}
+
+// parseJSONPath parses a JSONPath template such as '{[*].hostname}'
+func parseJSONPath(template string) ([]*jsonPathNode, error) {
+	root := &jsonPathNode{isRange: true}
+	stack := []*jsonPathNode{root}
+
+	for len(template) > 0 {
+		current := stack[len(stack)-1]
+
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			current.body = append(current.body, &jsonPathNode{text: template})
+			break
+		}
+		if open > 0 {
+			current.body = append(current.body, &jsonPathNode{text: template[:open]})
+		}
+
+		close := indexOutsideQuotes(template, open+1, '}')
+		if close < 0 {
+			return nil, fmt.Errorf("unclosed action in JSONPath %q", template)
+		}
+		action := strings.TrimSpace(template[open+1 : close])
+		template = template[close+1:]
+
+		switch {
+		case action == "end":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("unexpected {end} in JSONPath")
+			}
+			stack = stack[:len(stack)-1]
+
+		case strings.HasPrefix(action, "range "):
+			path, err := parseJSONPathSteps(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, err
+			}
+			node := &jsonPathNode{path: path, isRange: true}
+			current.body = append(current.body, node)
+			stack = append(stack, node)
+
+		case strings.HasPrefix(action, `"`):
+			text, err := strconv.Unquote(action)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string literal %s in JSONPath", action)
+			}
+			current.body = append(current.body, &jsonPathNode{text: text})
+
+		default:
+			path, err := parseJSONPathSteps(action)
+			if err != nil {
+				return nil, err
+			}
+			current.body = append(current.body, &jsonPathNode{path: path, isPath: true})
+		}
+	}
+
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("missing {end} for {range} in JSONPath")
+	}
+
+	return root.body, nil
+}
+
+// parseJSONPathSteps parses a path such as [0].ip_addresses[*].ipAddress
+func parseJSONPathSteps(expr string) ([]jsonPathStep, error) {
+	// The root and current node markers are implied
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), "@")
+
+	var steps []jsonPathStep
+	for i := 0; i < len(expr); {
+		switch expr[i] {
+		case '.':
+			if strings.HasPrefix(expr[i:], "..") {
+				name := readJSONPathName(expr[i+2:])
+				if name == "" {
+					return nil, fmt.Errorf("missing key after .. in JSONPath %q", expr)
+				}
+				steps = append(steps, jsonPathStep{kind: stepRecursive, name: name})
+				i += 2 + len(name)
+				continue
+			}
+
+			i++
+			if i < len(expr) && expr[i] == '*' {
+				steps = append(steps, jsonPathStep{kind: stepWildcard})
+				i++
+				continue
+			}
+
+			name := readJSONPathName(expr[i:])
+			if name == "" {
+				// A lone "." refers to the current value
+				if i == len(expr) {
+					continue
+				}
+				return nil, fmt.Errorf("unexpected %q in JSONPath %q", expr[i], expr)
+			}
+			steps = append(steps, jsonPathStep{kind: stepField, name: name})
+			i += len(name)
+
+		case '[':
+			close := indexClosingBracket(expr, i+1)
+			if close < 0 {
+				return nil, fmt.Errorf("unclosed [ in JSONPath %q", expr)
+			}
+			step, err := parseJSONPathBracket(strings.TrimSpace(expr[i+1 : close]))
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+			i = close + 1
+
+		default:
+			return nil, fmt.Errorf("unexpected %q in JSONPath %q", expr[i], expr)
+		}
+	}
+
+	return steps, nil
+}
+
+// parseJSONPathBracket parses the contents of a [...] step
+func parseJSONPathBracket(inner string) (jsonPathStep, error) {
+	switch {
+	case inner == "*":
+		return jsonPathStep{kind: stepWildcard}, nil
+
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		return parseJSONPathFilter(strings.TrimSpace(inner[2 : len(inner)-1]))
+
+	case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+		return jsonPathStep{kind: stepField, name: inner[1 : len(inner)-1]}, nil
+
+	case strings.Contains(inner, ":"):
+		step := jsonPathStep{kind: stepSlice}
+		start, end, _ := strings.Cut(inner, ":")
+		if start = strings.TrimSpace(start); start != "" {
+			n, err := strconv.Atoi(start)
+			if err != nil {
+				return step, fmt.Errorf("invalid slice [%s] in JSONPath", inner)
+			}
+			step.index, step.hasStart = n, true
+		}
+		if end = strings.TrimSpace(end); end != "" {
+			n, err := strconv.Atoi(end)
+			if err != nil {
+				return step, fmt.Errorf("invalid slice [%s] in JSONPath", inner)
+			}
+			step.end, step.hasEnd = n, true
+		}
+		return step, nil
+
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("invalid index [%s] in JSONPath", inner)
+		}
+		return jsonPathStep{kind: stepIndex, index: n}, nil
+	}
+}
+
+// parseJSONPathFilter parses a filter such as @.location_code == "NYC1"
+func parseJSONPathFilter(expr string) (jsonPathStep, error) {
+	step := jsonPathStep{kind: stepFilter}
+
+	left := expr
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if i := indexOutsideQuotes(expr, 0, op[0]); i >= 0 && strings.HasPrefix(expr[i:], op) {
+			left = strings.TrimSpace(expr[:i])
+			step.filterOp = op
+
+			arg, err := parseJSONPathLiteral(strings.TrimSpace(expr[i+len(op):]))
+			if err != nil {
+				return step, err
+			}
+			step.filterArg = arg
+			break
+		}
+	}
+
+	if !strings.HasPrefix(left, "@") {
+		return step, fmt.Errorf("filter %q in JSONPath must start with @", expr)
+	}
+
+	path, err := parseJSONPathSteps(left)
+	if err != nil {
+		return step, err
+	}
+	step.filterPath = path
+
+	return step, nil
+}
+
+// parseJSONPathLiteral parses the right hand side of a filter
+func parseJSONPathLiteral(literal string) (interface{}, error) {
+	switch {
+	case literal == "true":
+		return true, nil
+	case literal == "false":
+		return false, nil
+	case literal == "null":
+		return nil, nil
+	case len(literal) >= 2 && (literal[0] == '\'' || literal[0] == '"') && literal[len(literal)-1] == literal[0]:
+		return literal[1 : len(literal)-1], nil
+	}
+
+	n, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q in JSONPath filter", literal)
+	}
+	return n, nil
+}
+
+// readJSONPathName returns the key at the start of s
+func readJSONPathName(s string) string {
+	end := 0
+	for end < len(s) {
+		c := s[end]
+		if c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			end++
+			continue
+		}
+		break
+	}
+	return s[:end]
+}
+
+// indexClosingBracket returns the index of the ] closing a [ opened before start,
+// skipping nested brackets such as those of a filter on @.ips[0]
+func indexClosingBracket(s string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == quote:
+			quote = 0
+		case quote != 0:
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '[':
+			depth++
+		case s[i] == ']' && depth == 0:
+			return i
+		case s[i] == ']':
+			depth--
+		}
+	}
+	return -1
+}
+
+// indexOutsideQuotes returns the index of the first c at or after start that is not quoted
+func indexOutsideQuotes(s string, start int, c byte) int {
+	var quote byte
+	for i := start; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == '\\':
+			i++
+		case quote != 0 && s[i] == quote:
+			quote = 0
+		case quote == 0 && (s[i] == '"' || s[i] == '\''):
+			quote = s[i]
+		case quote == 0 && s[i] == c:
+			return i
+		}
+	}
+	return -1
+}
+
+// writeJSONPath evaluates a JSONPath template against the JSON form of data,
+// the same document -o json prints. Templates written against the {"data": ...}
+// envelope of the API, such as '{.data[*].hostname}', are accepted as well.
+func writeJSONPath(w io.Writer, template string, data interface{}) error {
+	nodes, err := parseJSONPath(template)
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	// Decode numbers as json.Number so IDs are printed as they are
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	var root interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return err
+	}
+	if usesDataEnvelope(nodes, root) {
+		root = map[string]interface{}{"data": root}
+	}
+
+	var buf bytes.Buffer
+	if err := executeJSONPath(&buf, nodes, root); err != nil {
+		return err
+	}
+
+	return writeLine(w, buf.Bytes())
+}
+
+// usesDataEnvelope reports whether the top-level paths of a template start with .data
+// while the document has no data field, so they refer to the API envelope
+func usesDataEnvelope(nodes []*jsonPathNode, root interface{}) bool {
+	if object, ok := root.(map[string]interface{}); ok {
+		if _, ok := object["data"]; ok {
+			return false
+		}
+	}
+
+	found := false
+	for _, node := range nodes {
+		if !node.isPath && !node.isRange {
+			continue
+		}
+		if len(node.path) == 0 || node.path[0].kind != stepField || node.path[0].name != "data" {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// executeJSONPath prints nodes evaluated against value
+func executeJSONPath(buf *bytes.Buffer, nodes []*jsonPathNode, value interface{}) error {
+	for _, node := range nodes {
+		switch {
+		case node.isRange:
+			results, err := evalJSONPath(node.path, []interface{}{value})
+			if err != nil {
+				return err
+			}
+
+			// Ranging over a single list iterates over its elements
+			if len(results) == 1 {
+				if list, ok := results[0].([]interface{}); ok {
+					results = list
+				}
+			}
+
+			for _, result := range results {
+				if err := executeJSONPath(buf, node.body, result); err != nil {
+					return err
+				}
+			}
+
+		case node.isPath:
+			results, err := evalJSONPath(node.path, []interface{}{value})
+			if err != nil {
+				return err
+			}
+
+			for i, result := range results {
+				if i > 0 {
+					buf.WriteByte(' ')
+				}
+				buf.WriteString(formatJSONPathValue(result))
+			}
+
+		default:
+			buf.WriteString(node.text)
+		}
+	}
+
+	return nil
+}
+
+// evalJSONPath applies the steps of a path to a set of values
+func evalJSONPath(steps []jsonPathStep, values []interface{}) ([]interface{}, error) {
+	for _, step := range steps {
+		var next []interface{}
+
+		switch step.kind {
+		case stepField:
+			found := false
+			for _, value := range values {
+				if object, ok := value.(map[string]interface{}); ok {
+					if v, ok := object[step.name]; ok {
+						next = append(next, v)
+						found = true
+					}
+				}
+			}
+			if !found && len(values) > 0 {
+				return nil, fmt.Errorf("%s is not found", step.name)
+			}
+
+		case stepRecursive:
+			for _, value := range values {
+				next = append(next, findRecursive(value, step.name)...)
+			}
+
+		case stepWildcard:
+			for _, value := range values {
+				next = append(next, children(value)...)
+			}
+
+		case stepIndex:
+			for _, value := range values {
+				list, ok := value.([]interface{})
+				if !ok {
+					continue
+				}
+				i := step.index
+				if i < 0 {
+					i += len(list)
+				}
+				if i < 0 || i >= len(list) {
+					return nil, fmt.Errorf("array index [%d] out of bounds", step.index)
+				}
+				next = append(next, list[i])
+			}
+
+		case stepSlice:
+			for _, value := range values {
+				list, ok := value.([]interface{})
+				if !ok {
+					continue
+				}
+				start, end := 0, len(list)
+				if step.hasStart {
+					start = clampIndex(step.index, len(list))
+				}
+				if step.hasEnd {
+					end = clampIndex(step.end, len(list))
+				}
+				if start < end {
+					next = append(next, list[start:end]...)
+				}
+			}
+
+		case stepFilter:
+			for _, value := range values {
+				for _, child := range children(value) {
+					if matchesJSONPathFilter(step, child) {
+						next = append(next, child)
+					}
+				}
+			}
+		}
+
+		values = next
+	}
+
+	return values, nil
+}
+
+// children returns the elements of a list or the values of an object, ordered by key
+func children(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		values := make([]interface{}, 0, len(v))
+		for _, key := range keys {
+			values = append(values, v[key])
+		}
+		return values
+	}
+	return nil
+}
+
+// findRecursive returns the values of every key named name under value
+func findRecursive(value interface{}, name string) []interface{} {
+	var found []interface{}
+	if object, ok := value.(map[string]interface{}); ok {
+		if v, ok := object[name]; ok {
+			found = append(found, v)
+		}
+	}
+	for _, child := range children(value) {
+		found = append(found, findRecursive(child, name)...)
+	}
+	return found
+}
+
+// clampIndex converts a possibly negative slice bound into a valid index
+func clampIndex(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	return max(0, min(i, length))
+}
+
+// matchesJSONPathFilter reports whether value passes a filter step
+func matchesJSONPathFilter(step jsonPathStep, value interface{}) bool {
+	results, err := evalJSONPath(step.filterPath, []interface{}{value})
+	if err != nil || len(results) == 0 {
+		return false
+	}
+	if step.filterOp == "" {
+		return true
+	}
+
+	left, right := results[0], step.filterArg
+
+	// Compare numbers numerically
+	if l, ok := jsonPathNumber(left); ok {
+		if r, ok := jsonPathNumber(right); ok {
+			switch step.filterOp {
+			case "==":
+				return l == r
+			case "!=":
+				return l != r
+			case "<":
+				return l < r
+			case "<=":
+				return l <= r
+			case ">":
+				return l > r
+			case ">=":
+				return l >= r
+			}
+		}
+	}
+
+	// Compare strings lexically
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			switch step.filterOp {
+			case "<":
+				return l < r
+			case "<=":
+				return l <= r
+			case ">":
+				return l > r
+			case ">=":
+				return l >= r
+			}
+		}
+	}
+
+	switch step.filterOp {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	}
+	return false
+}
+
+// jsonPathNumber converts a decoded JSON number or a numeric string to a float
+func jsonPathNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		// Prices are returned as strings, e.g. "99.00"
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// formatJSONPathValue formats a result, printing strings without quotes
+func formatJSONPathValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSONPath(t *testing.T) {
+	servers := []map[string]interface{}{
+		{"service_id": 500001, "hostname": "web-01", "datacenter_name": "NYC1", "ips": []string{"192.0.2.1", "192.0.2.2"}},
+		{"service_id": 500002, "hostname": "web-02", "datacenter_name": "AMS1", "ips": []string{"192.0.2.3"}},
+		{"service_id": 500003, "hostname": "db-01", "datacenter_name": "NYC1", "ips": []string{}},
+	}
+	server := map[string]interface{}{"hostname": "web-01", "power": map[string]interface{}{"on": true}, "friendly-name": "Web"}
+
+	tests := []struct {
+		name     string
+		template string
+		data     interface{}
+		want     string
+		wantErr  string
+	}{
+		{name: "list root", template: "{[*].hostname}", data: servers, want: "web-01 web-02 db-01"},
+		{name: "data envelope", template: "{.data[*].hostname}", data: servers, want: "web-01 web-02 db-01"},
+		{name: "data envelope with dollar", template: "{$.data[0].service_id}", data: servers, want: "500001"},
+		{name: "data envelope range", template: `{range .data[*]}{.hostname}{"\n"}{end}`, data: servers, want: "web-01\nweb-02\ndb-01\n"},
+		{name: "data field of the document", template: "{.data}", data: map[string]interface{}{"data": "own"}, want: "own"},
+		{name: "dollar root", template: "{$[0].service_id}", data: servers, want: "500001"},
+		{name: "object root", template: "{.hostname}", data: server, want: "web-01"},
+		{name: "nested field", template: "{.power.on}", data: server, want: "true"},
+		{name: "quoted field", template: "{['friendly-name']}", data: server, want: "Web"},
+		{name: "negative index", template: "{[-1].hostname}", data: servers, want: "db-01"},
+		{name: "slice", template: "{[0:2].hostname}", data: servers, want: "web-01 web-02"},
+		{name: "open slice", template: "{[1:].service_id}", data: servers, want: "500002 500003"},
+		{name: "recursive", template: "{..hostname}", data: servers, want: "web-01 web-02 db-01"},
+		{name: "nested wildcard", template: "{[*].ips[*]}", data: servers, want: "192.0.2.1 192.0.2.2 192.0.2.3"},
+		{name: "filter equal", template: `{[?(@.datacenter_name=="NYC1")].hostname}`, data: servers, want: "web-01 db-01"},
+		{name: "filter not equal", template: `{[?(@.datacenter_name != 'NYC1')].hostname}`, data: servers, want: "web-02"},
+		{name: "filter number", template: "{[?(@.service_id > 500001)].hostname}", data: servers, want: "web-02 db-01"},
+		{name: "filter existence", template: "{[?(@.ips[0])].hostname}", data: servers, want: "web-01 web-02"},
+		{name: "range", template: `{range [*]}{.service_id}{"\t"}{.hostname}{"\n"}{end}`, data: servers, want: "500001\tweb-01\n500002\tweb-02\n500003\tdb-01\n"},
+		{name: "literal text", template: "host={.hostname}", data: server, want: "host=web-01"},
+		{name: "missing key", template: "{.nope}", data: server, wantErr: "nope is not found"},
+		{name: "index out of bounds", template: "{[5]}", data: servers, wantErr: "out of bounds"},
+		{name: "unclosed action", template: "{.hostname", data: server, wantErr: "unclosed action"},
+		{name: "unexpected end", template: "{end}", data: server, wantErr: "unexpected {end}"},
+		{name: "missing end", template: "{range [*]}{.hostname}", data: servers, wantErr: "missing {end}"},
+		{name: "invalid index", template: "{[x]}", data: servers, wantErr: "invalid index"},
+		{name: "filter without @", template: `{[?(.hostname=="a")]}`, data: servers, wantErr: "must start with @"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeJSONPath(&buf, tt.template, tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := tt.want
+			if !strings.HasSuffix(want, "\n") {
+				want += "\n"
+			}
+			if got := buf.String(); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormats lists the values accepted by --output
+var outputFormats = []string{"table", "json", "yaml", "csv"}
+
+// outputTemplate and outputJSONPath select fields to print, overriding --output
+var (
+	outputTemplate string
+	outputJSONPath string
+)
+
+// outputFormat returns the output format selected with --output.
+// --template and --jsonpath select the "template" and "jsonpath" formats.
+func outputFormat() string {
+	if outputTemplate != "" {
+		return "template"
+	}
+	if outputJSONPath != "" {
+		return "jsonpath"
+	}
+
+	format := strings.ToLower(viper.GetString("output"))
+	if format == "" {
+		return "table"
+	}
+	return format
+}
+
+// validateOutputFormat checks that the selected output format is supported.
+// Templates are parsed up front so syntax errors are reported before calling the API.
+func validateOutputFormat() error {
+	if outputTemplate != "" && outputJSONPath != "" {
+		return fmt.Errorf("--template and --jsonpath cannot be used together")
+	}
+	if outputTemplate != "" {
+		_, err := parseOutputTemplate(outputTemplate)
+		return err
+	}
+	if outputJSONPath != "" {
+		_, err := parseJSONPath(outputJSONPath)
+		return err
+	}
+
+	format := outputFormat()
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format %q (must be one of: %s)", format, strings.Join(outputFormats, ", "))
+}
+
+// isTableOutput reports whether human readable output is selected
+func isTableOutput() bool {
+	return outputFormat() == "table"
+}
+
+// printStructured writes data in the selected machine readable output format.
+// Commands print their own tables when isTableOutput reports true.
+func printStructured(data interface{}) error {
+	switch outputFormat() {
+	case "template":
+		return writeTemplate(os.Stdout, outputTemplate, data)
+	case "jsonpath":
+		return writeJSONPath(os.Stdout, outputJSONPath, data)
+	case "yaml":
+		return writeYAML(os.Stdout, data)
+	case "csv":
+		return writeCSV(os.Stdout, data)
+	default:
+		return writeJSON(os.Stdout, data)
+	}
+}
+
+// writeJSON writes data as indented JSON
+func writeJSON(w io.Writer, data interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// writeYAML writes data as YAML, using the same field names as the JSON output
+func writeYAML(w io.Writer, data interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, decoding it into a node keeps the field order
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonData, &node); err != nil {
+		return err
+	}
+	clearYAMLStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// templateFuncs are the functions available to --template, in addition to the text/template builtins
+var templateFuncs = template.FuncMap{
+	// json encodes a value as compact JSON
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// join joins the elements of a list with a separator, e.g. {{.SSHKeys | join ","}}
+	"join": func(sep string, v interface{}) string {
+		value := reflect.ValueOf(v)
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			return fmt.Sprint(v)
+		}
+		var parts []string
+		for i := 0; i < value.Len(); i++ {
+			parts = append(parts, fmt.Sprint(value.Index(i).Interface()))
+		}
+		return strings.Join(parts, sep)
+	},
+}
+
+// parseOutputTemplate parses a --template value
+func parseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// writeTemplate executes a Go template against data. Fields use the Go names, e.g. {{.PublicIP}}.
+func writeTemplate(w io.Writer, text string, data interface{}) error {
+	tmpl, err := parseOutputTemplate(text)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("error executing template: %w", err)
+	}
+
+	return writeLine(w, buf.Bytes())
+}
+
+// writeLine writes data, adding a trailing newline if it has none
+func writeLine(w io.Writer, data []byte) error {
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// clearYAMLStyle switches a node decoded from JSON to block style
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// writeCSV writes a struct or a slice of structs as CSV, one row per struct.
+// Columns are named after the JSON fields. Nested values are encoded as JSON.
+func writeCSV(w io.Writer, data interface{}) error {
+	value := reflect.ValueOf(data)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	var items []reflect.Value
+	elemType := value.Type()
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		elemType = elemType.Elem()
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, value.Index(i))
+		}
+	} else {
+		items = append(items, value)
+	}
+
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	writer := csv.NewWriter(w)
+
+	if elemType.Kind() != reflect.Struct {
+		// Scalar values are written in a single column
+		writer.Write([]string{"value"})
+		for _, item := range items {
+			writer.Write([]string{csvValue(item)})
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+
+	var headers []string
+	for _, field := range csvFields(elemType, nil) {
+		headers = append(headers, field.name)
+	}
+	writer.Write(headers)
+
+	for _, item := range items {
+		var row []string
+		for _, field := range csvFields(elemType, nil) {
+			row = append(row, csvValue(fieldByIndex(item, field.index)))
+		}
+		writer.Write(row)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvField is a column of the CSV output
+type csvField struct {
+	name  string
+	index []int
+}
+
+// csvFields lists the JSON fields of a struct type, flattening embedded structs
+func csvFields(t reflect.Type, parent []int) []csvField {
+	var fields []csvField
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			fields = append(fields, csvFields(fieldType, index)...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, csvField{name: name, index: index})
+	}
+
+	return fields
+}
+
+// fieldByIndex returns a nested field, or an invalid value if an embedded pointer is nil
+func fieldByIndex(value reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		for value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return reflect.Value{}
+			}
+			value = value.Elem()
+		}
+		value = value.Field(i)
+	}
+	return value
+}
+
+// csvValue formats a value for a CSV cell
+func csvValue(value reflect.Value) string {
+	if !value.IsValid() {
+		return ""
+	}
+
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		data, err := json.Marshal(value.Interface())
+		if err != nil {
+			return ""
+		}
+		return string(bytes.TrimSpace(data))
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// actionResult is the machine readable result of a command changing a resource
+type actionResult struct {
+	ServiceID int    `json:"service_id,omitempty"` // Service ID of the server acted on
+	Action    string `json:"action"`               // Action performed
+	Success   bool   `json:"success"`              // Whether the API reported success
+	Message   string `json:"message,omitempty"`    // Human readable outcome
+}
+
+// newActionResult builds the result of an action on the server with the given service ID
+func newActionResult(serviceID int, action string, success bool, message string) actionResult {
+	return actionResult{ServiceID: serviceID, Action: action, Success: success, Message: message}
+}
+
+// printActionResult prints the outcome of a command changing a resource.
+// It returns an error with the exitRejected code if the action failed, which
+// Execute does not print again.
+func printActionResult(result actionResult) error {
+	if !isTableOutput() {
+		if err := printStructured(result); err != nil {
+			return err
+		}
+	} else if result.Success {
+		fmt.Printf("%s\n", BlueHeading(result.Message))
+	} else {
+		fmt.Fprintf(os.Stderr, "%s\n", RedText(result.Message))
+	}
+
+	if !result.Success {
+		return &exitError{code: exitRejected, err: errors.New(result.Message), silent: true}
+	}
+	return nil
+}
+
+// orderResult is the machine readable result of placing an order
+type orderResult struct {
+	ServiceIDs []int               `json:"service_ids"`       // Service IDs of the ordered servers
+	Order      icsapi.OrderRequest `json:"order"`             // Order as submitted
+	Servers    []newServerResult   `json:"servers,omitempty"` // Access details of the provisioned servers, with --wait
+	Quote      *icsapi.Quote       `json:"quote,omitempty"`   // Estimated monthly price of the order
+	DryRun     bool                `json:"dry_run,omitempty"` // Whether the order was only checked, with --dry-run
+}
+
+// newServerResult is the machine readable access details of a newly provisioned server
+type newServerResult struct {
+	ServiceID int      `json:"service_id"` // Service ID of the server
+	Hostname  string   `json:"hostname"`   // Server hostname
+	PrimaryIP string   `json:"primary_ip"` // Primary public IP address
+	Username  string   `json:"username"`   // Username for OS login
+	SSHKeys   []string `json:"ssh_keys"`   // Labels of the SSH keys assigned to the server
+}
+
+// linkResult is the machine readable result of a remote access command
+type linkResult struct {
+	ServiceID int    `json:"service_id"` // Service ID of the server
+	Type      string `json:"type"`       // Type of access, "ikvm" or "sol"
+	Link      string `json:"link"`       // Access link
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage profiles for multiple Ingenuity Cloud Services accounts",
+	Long: `Profiles hold the API key, API URL and default settings of an account.
+
+The top-level keys of the config file are the "default" profile. Named profiles
+are created with 'ics-cli auth login --profile NAME' and kept under the "profiles"
+key. Commands act on the profile given with --profile or ICS_PROFILE, otherwise on
+the current profile selected with 'ics-cli profile use'.`,
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+}
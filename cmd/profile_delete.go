@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// profileDeleteCmd represents the profile delete command
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete [profile]",
+	Short: "Delete a profile and its API key",
+	Long: `Delete a named profile with its API key and settings. If it was the current
+profile, the default profile becomes current. The default profile cannot be
+deleted, use 'ics-cli auth logout' to remove its API key.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if name == defaultProfile {
+			return usageError("the default profile cannot be deleted. Use 'ics-cli auth logout' to remove its API key")
+		}
+
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		if !config.hasProfile(name) {
+			return &exitError{code: exitNotFound, err: fmt.Errorf("profile %s does not exist", name)}
+		}
+
+		// Check if user wants to proceed
+		dontPrompt, _ := cmd.Flags().GetBool("dont")
+		if !dontPrompt {
+			fmt.Fprintf(os.Stderr, "Are you sure you want to delete profile %s and its API key? (y/n): ", name)
+			var response string
+			fmt.Scanln(&response)
+			if response != "y" {
+				return abortedError("no user confirmation. Aborting")
+			}
+		}
+
+		deleteAPIKey(config, name)
+		config.deleteProfile(name)
+		if err := config.save(); err != nil {
+			return err
+		}
+
+		return printActionResult(actionResult{Action: "delete", Success: true, Message: fmt.Sprintf("Successfully deleted profile %s", name)})
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileDeleteCmd)
+
+	profileDeleteCmd.Flags().BoolP("dont", "d", false, "Don't prompt for confirmation")
+}
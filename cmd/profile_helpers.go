@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProfile is the profile made of the top-level keys of the config file
+const defaultProfile = "default"
+
+// profileAccountKeys are the settings a named profile never inherits from the top-level
+// keys, so a command cannot act on the account of another profile
+var profileAccountKeys = []string{"api_key", "api_key_ref"}
+
+// profileNamePattern matches valid profile names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// activeProfile is the profile commands act on, and namedProfiles whether the config
+// file has any named profiles. Both are set by initConfig.
+var (
+	activeProfile = defaultProfile
+	namedProfiles bool
+)
+
+// configFile is the config file as written, without flags, environment variables or
+// the active profile applied. Named profiles are kept under the "profiles" key.
+type configFile struct {
+	path     string                 // Path of the file, which may not exist yet
+	settings map[string]interface{} // Top-level keys
+}
+
+// configFilePath returns the path of the config file in use, or the default one
+func configFilePath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ics-cli.yaml"), nil
+}
+
+// readConfigFile reads the config file. A missing file reads as empty.
+func readConfigFile() (*configFile, error) {
+	path, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &configFile{path: path, settings: make(map[string]interface{})}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &config.settings); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	if config.settings == nil {
+		config.settings = make(map[string]interface{})
+	}
+	return config, nil
+}
+
+// save writes the config file, readable by the current user only
+func (c *configFile) save() error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.settings); err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	return nil
+}
+
+// profiles returns the named profiles, keyed by name
+func (c *configFile) profiles() map[string]interface{} {
+	profiles, _ := c.settings["profiles"].(map[string]interface{})
+	return profiles
+}
+
+// hasProfile reports whether a profile exists. The default profile always does.
+func (c *configFile) hasProfile(name string) bool {
+	if name == defaultProfile {
+		return true
+	}
+	_, ok := c.profiles()[name]
+	return ok
+}
+
+// profileNames returns the default profile and the named profiles, sorted by name
+func (c *configFile) profileNames() []string {
+	names := slices.Sorted(maps.Keys(c.profiles()))
+	return append([]string{defaultProfile}, slices.DeleteFunc(names, func(name string) bool { return name == defaultProfile })...)
+}
+
+// profile returns the settings of a profile, or nil if it does not exist.
+// The settings of the default profile are the top-level keys.
+func (c *configFile) profile(name string) map[string]interface{} {
+	if name == defaultProfile {
+		settings := maps.Clone(c.settings)
+		delete(settings, "profiles")
+		delete(settings, "current_profile")
+		return settings
+	}
+
+	settings, _ := c.profiles()[name].(map[string]interface{})
+	if settings == nil && c.hasProfile(name) {
+		// A profile with no settings
+		settings = make(map[string]interface{})
+	}
+	return settings
+}
+
+// setProfileValue sets a setting of a profile, creating the profile if needed
+func (c *configFile) setProfileValue(name, key string, value interface{}) {
+	if name == defaultProfile {
+		c.settings[key] = value
+		return
+	}
+
+	profiles := c.profiles()
+	if profiles == nil {
+		profiles = make(map[string]interface{})
+		c.settings["profiles"] = profiles
+	}
+	settings, _ := profiles[name].(map[string]interface{})
+	if settings == nil {
+		settings = make(map[string]interface{})
+		profiles[name] = settings
+	}
+	settings[key] = value
+}
+
+// unsetProfileValue removes a setting from a profile
+func (c *configFile) unsetProfileValue(name, key string) {
+	if name == defaultProfile {
+		delete(c.settings, key)
+		return
+	}
+	if settings, ok := c.profiles()[name].(map[string]interface{}); ok {
+		delete(settings, key)
+	}
+}
+
+// deleteProfile removes a named profile, and makes the default profile current if it was
+func (c *configFile) deleteProfile(name string) {
+	delete(c.profiles(), name)
+	if len(c.profiles()) == 0 {
+		delete(c.settings, "profiles")
+	}
+	if c.currentProfile() == name {
+		delete(c.settings, "current_profile")
+	}
+}
+
+// currentProfile returns the profile selected with "ics-cli profile use"
+func (c *configFile) currentProfile() string {
+	if name, ok := c.settings["current_profile"].(string); ok && name != "" {
+		return name
+	}
+	return defaultProfile
+}
+
+// validateProfileName checks that a profile name can be used as a config key
+func validateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return usageError("invalid profile name %q. Use letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// selectProfile picks the active profile from --profile, ICS_PROFILE or the current
+// profile of the config file, and applies its settings over the top-level keys.
+// Flags and environment variables still take precedence over the profile.
+func selectProfile() {
+	activeProfile = viper.GetString("profile")
+
+	config, err := readConfigFile()
+	if err != nil {
+		if activeProfile == "" {
+			activeProfile = defaultProfile
+		}
+		return
+	}
+
+	namedProfiles = len(config.profiles()) > 0
+	if activeProfile == "" {
+		activeProfile = config.currentProfile()
+	}
+	if activeProfile == defaultProfile {
+		return
+	}
+
+	settings := make(map[string]interface{})
+	for _, key := range profileAccountKeys {
+		settings[key] = ""
+	}
+	maps.Copy(settings, config.profile(activeProfile))
+	viper.MergeConfigMap(settings)
+}
+
+// showActiveProfile prints the profile a command acts on to stderr, once named profiles are in use
+func showActiveProfile() {
+	if !namedProfiles {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s %s\n", BlueHeading("Profile:"), YellowText(activeProfile))
+}
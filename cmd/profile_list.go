@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+)
+
+// profileInfo is the machine readable summary of a profile
+type profileInfo struct {
+	Name     string `json:"name"`      // Profile name
+	Active   bool   `json:"active"`    // Whether commands act on this profile
+	APIURL   string `json:"api_url"`   // API URL of the profile, empty for the default URL
+	LoggedIn bool   `json:"logged_in"` // Whether the profile has an API key
+}
+
+// profileListCmd represents the profile list command
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		var profiles []profileInfo
+		for _, name := range config.profileNames() {
+			settings := config.profile(name)
+			apiKey, _ := settings["api_key"].(string)
+			apiKeyRef, _ := settings["api_key_ref"].(string)
+			apiURL, _ := settings["api_url"].(string)
+			profiles = append(profiles, profileInfo{
+				Name:     name,
+				Active:   name == activeProfile,
+				APIURL:   apiURL,
+				LoggedIn: apiKey != "" || apiKeyRef != "",
+			})
+		}
+
+		if !isTableOutput() {
+			return printStructured(profiles)
+		}
+
+		headerFmt := color.New(color.FgBlue, color.Bold).SprintfFunc()
+		columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+		tbl := table.New("", "Name", "API URL", "Logged In")
+		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+		for _, profile := range profiles {
+			active := ""
+			if profile.Active {
+				active = "*"
+			}
+			apiURL := profile.APIURL
+			if apiURL == "" {
+				apiURL = "-"
+			}
+			loggedIn := "No"
+			if profile.LoggedIn {
+				loggedIn = "Yes"
+			}
+			tbl.AddRow(active, profile.Name, apiURL, loggedIn)
+		}
+
+		tbl.Print()
+		if !config.hasProfile(activeProfile) {
+			fmt.Printf("\n%s\n", RedText(fmt.Sprintf("Profile %s does not exist", activeProfile)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+}
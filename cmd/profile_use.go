@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// profileUseCmd represents the profile use command
+var profileUseCmd = &cobra.Command{
+	Use:   "use [profile]",
+	Short: "Set the current profile",
+	Long: `Set the profile commands act on when neither --profile nor ICS_PROFILE is given.
+Use "default" to go back to the top-level keys of the config file.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		config, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		if !config.hasProfile(name) {
+			return &exitError{code: exitNotFound, err: fmt.Errorf("profile %s does not exist. Run 'ics-cli auth login --profile %s' to create it", name, name)}
+		}
+
+		if name == defaultProfile {
+			delete(config.settings, "current_profile")
+		} else {
+			config.settings["current_profile"] = name
+		}
+		if err := config.save(); err != nil {
+			return err
+		}
+
+		return printActionResult(actionResult{Action: "use", Success: true, Message: fmt.Sprintf("Switched to profile %s", name)})
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileUseCmd)
+}
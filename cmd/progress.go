@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progress shows the status of a long running wait on stderr. On a terminal a single
+// line is redrawn every second with the elapsed time; otherwise each new status is
+// printed on its own line, which keeps CI logs readable.
+type progress struct {
+	mu      sync.Mutex
+	message string
+	start   time.Time
+	tty     bool
+	done    chan struct{}
+	stopped sync.Once
+}
+
+// newProgress starts showing a status line
+func newProgress(message string) *progress {
+	p := &progress{
+		start: time.Now(),
+		tty:   term.IsTerminal(int(os.Stderr.Fd())),
+		done:  make(chan struct{}),
+	}
+	p.Update(message)
+
+	if p.tty {
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-p.done:
+					return
+				case <-ticker.C:
+					p.mu.Lock()
+					p.draw()
+					p.mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	return p
+}
+
+// Update changes the status shown
+func (p *progress) Update(message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if message == p.message {
+		return
+	}
+	p.message = message
+
+	if p.tty {
+		p.draw()
+	} else {
+		fmt.Fprintln(os.Stderr, message)
+	}
+}
+
+// Log prints a line above the status line
+func (p *progress) Log(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tty {
+		fmt.Fprintf(os.Stderr, "\r\033[K%s\n", line)
+		p.draw()
+	} else {
+		fmt.Fprintln(os.Stderr, line)
+	}
+}
+
+// draw redraws the status line. The caller must hold p.mu.
+func (p *progress) draw() {
+	elapsed := time.Since(p.start).Round(time.Second)
+	fmt.Fprintf(os.Stderr, "\r\033[K%s %s", p.message, WhiteText(fmt.Sprintf("(%s)", elapsed)))
+}
+
+// Stop clears the status line
+func (p *progress) Stop() {
+	p.stopped.Do(func() {
+		close(p.done)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.tty {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+	})
+}
@@ -1,27 +1,103 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/UK2Group/ics-cli/icsapi"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
 
+// envPrefix is the prefix of the environment variables overriding settings and global flags.
+// ICS_WATCH_ is reserved for the variables passed to inventory watch hooks.
+const envPrefix = "ICS"
+
+// flagEnvVars are the environment variables of the global flags that are not config keys.
+// Config keys are read from ICS_<KEY> by viper.
+var flagEnvVars = map[string]string{
+	"config":   "ICS_CONFIG_FILE",
+	"template": "ICS_TEMPLATE",
+	"jsonpath": "ICS_JSONPATH",
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "ics-cli",
 	Short: "CLI Application for Ingenuity Cloud Services API",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Cobra has parsed and validated the command line by now
+		commandStarted = true
+
+		// Unlocked credential files are forgotten once their cache expires
+		pruneUnlockCaches()
+
+		if err := validateOutputFormat(); err != nil {
+			return &exitError{code: exitUsage, err: err}
+		}
+
+		// The config commands still run, to find and fix the file
+		if configErr != nil && !isConfigCommand(cmd) {
+			return fmt.Errorf("%w. Run 'ics-cli config validate' to check it", configErr)
+		}
+		return nil
+	},
+	// Errors are printed by Execute, along with a usage hint for usage errors
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// configErr is the error reading the config file, if any. It is set by initConfig.
+var configErr error
+
+// isConfigCommand reports whether a command is help or one of the config commands
+func isConfigCommand(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd == configCmd || cmd.Name() == "help" {
+			return true
+		}
+	}
+	return false
 }
 
+// commandStarted is set once cobra has validated the command line, so errors
+// returned before it are usage errors
+var commandStarted bool
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The process exits with one of the codes documented in exit.go.
 func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(1)
+	// Cancel in-flight requests and waits on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	stop()
+	if err == nil {
+		return
+	}
+
+	if !commandStarted {
+		err = &exitError{code: exitUsage, err: err}
+	}
+
+	var exitErr *exitError
+	if !errors.As(err, &exitErr) || !exitErr.silent {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+
+	code := exitCode(err)
+	if code == exitUsage {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
 	}
+	os.Exit(code)
 }
 
 func init() {
@@ -31,12 +107,38 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.ics-cli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.ics-cli.yaml, or ICS_CONFIG_FILE)")
+
+	// Every config key can be set with an ICS_ environment variable, e.g. ICS_API_URL for
+	// api_url. Flags take precedence over environment variables, and both over the config file.
+	viper.SetEnvPrefix(envPrefix)
+	viper.AutomaticEnv()
+
+	// Named profiles hold the API key and settings of different accounts
+	rootCmd.PersistentFlags().String("profile", "", "profile to use (default is the current profile, see 'ics-cli profile list')")
+	viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	rootCmd.PersistentFlags().String("api-url", "", "base URL of the ICS API (default is "+icsapi.DefaultBaseURL+")")
+
+	// The API URL can be set by flag, ICS_API_URL or the api_url config key
+	viper.BindPFlag("api_url", rootCmd.PersistentFlags().Lookup("api-url"))
+
+	// Idempotent requests are retried on transient failures
+	rootCmd.PersistentFlags().Int("retries", icsapi.DefaultRetryPolicy.MaxRetries, "number of retries for idempotent API requests on transient failures")
+	viper.BindPFlag("retries", rootCmd.PersistentFlags().Lookup("retries"))
 
+	// Output format shared by every command
+	rootCmd.PersistentFlags().StringP("output", "o", "table", "output format: "+strings.Join(outputFormats, ", "))
+	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+
+	// Select fields to print with a Go template or a JSONPath expression, like kubectl
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "template", "", "Go template applied to the output, e.g. '{{.PublicIP}}'")
+	rootCmd.PersistentFlags().StringVar(&outputJSONPath, "jsonpath", "", "JSONPath expression applied to the output, e.g. '{[*].hostname}'")
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
+	applyFlagEnvVars()
+
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
@@ -51,10 +153,28 @@ func initConfig() {
 		viper.SetConfigName(".ics-cli")
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	// If a config file is found, read it in. A missing file is not an error.
+	var notFound viper.ConfigFileNotFoundError
+	if err := viper.ReadInConfig(); err != nil && !errors.As(err, &notFound) && !errors.Is(err, fs.ErrNotExist) {
+		configErr = fmt.Errorf("error reading config file: %w", err)
+	}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		//fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	// Apply the settings of the active profile over the top-level keys
+	selectProfile()
+}
+
+// applyFlagEnvVars sets the global flags that are not config keys from their environment
+// variables, unless they were given on the command line
+func applyFlagEnvVars() {
+	for name, env := range flagEnvVars {
+		flag := rootCmd.PersistentFlags().Lookup(name)
+		if value := os.Getenv(env); value != "" && !flag.Changed {
+			flag.Value.Set(value)
+		}
 	}
 }
+
+// envVarName returns the environment variable of a config key, e.g. ICS_API_URL for api_url
+func envVarName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(key)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/UK2Group/ics-cli/icsapi"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +18,7 @@ var sshKeysAddCmd = &cobra.Command{
   
   # Add an SSH key directly with a multi-word name
   ics-cli sshkeys add --name "My Production Key" --key "ssh-rsa AAAAB3..."`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get command flags
 		name, _ := cmd.Flags().GetString("name")
 		key, _ := cmd.Flags().GetString("key")
@@ -25,8 +26,7 @@ var sshKeysAddCmd = &cobra.Command{
 
 		// Validate required parameters
 		if name == "" {
-			fmt.Fprintln(os.Stderr, "Error: SSH Key name is required (--name)")
-			return
+			return usageError("SSH Key name is required (--name)")
 		}
 
 		// Get the SSH key from either file or direct input
@@ -35,37 +35,36 @@ var sshKeysAddCmd = &cobra.Command{
 			// Read from file
 			keyData, err := os.ReadFile(filePath)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading SSH key file: %s\n", err)
-				return
+				return fmt.Errorf("failed to read SSH key file: %w", err)
 			}
 			sshKey = string(keyData)
 		} else if key != "" {
 			// Use direct input
 			sshKey = key
 		} else {
-			fmt.Fprintln(os.Stderr, "Error: Either SSH key (--key) or key file (--file) is required")
-			return
+			return usageError("either SSH key (--key) or key file (--file) is required")
 		}
 
 		// Clean up the key (remove extra whitespace, comments, etc.)
 		sshKey = cleanSSHKey(sshKey)
 
-		// Create request body
-		requestData := map[string]string{
-			"label":      name,
-			"public_key": sshKey,
+		client, err := newAPIClient()
+		if err != nil {
+			return err
 		}
 
 		// Add the SSH key
-		id, err := addSSHKey(requestData)
+		id, err := client.AddSSHKey(cmd.Context(), name, sshKey)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error adding SSH key: %s\n", err)
-			return
+			return fmt.Errorf("failed to add SSH key: %w", err)
 		}
 
-		if id {
-			fmt.Printf("%s\n", BlueHeading("Successfully added SSH Key"))
+		if !isTableOutput() {
+			return printStructured(icsapi.SSHKey{ID: id, Label: name, Key: sshKey})
 		}
+
+		fmt.Printf("%s\n", BlueHeading("Successfully added SSH Key"))
+		return nil
 	},
 }
 
@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-	"strconv"
-
 	"github.com/spf13/cobra"
 )
 
@@ -16,42 +12,38 @@ var sshKeyAssignCmd = &cobra.Command{
   # Assign an existing SSH Key to a Baremetal Server with Service ID 123456
   ics-cli sshkeys assign my-ssh-key --server 123456`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
 		// Get the SSH Key Name from the arguments
 		sshKeyName := args[0]
 
-		// Check if Service ID is provided
-		serviceID, _ := cmd.Flags().GetString("server")
-		if serviceID == "" {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be provided with --server or -s flag")
-			return
+		// Check if a server is provided
+		query, _ := cmd.Flags().GetString("server")
+		if query == "" {
+			return usageError("server must be provided with --server or -s flag")
 		}
 
-		// Get the Server ID from Service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
+		client, err := newAPIClient()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
-		// Get the SSH Key ID from the Label
-		sshKey, err := getSSHKeyFromLabel(sshKeyName)
+		// Resolve the server argument
+		server, err := client.ResolveServer(cmd.Context(), query)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
-		// Get existing SSH keys assigned to the server
-		// Convert serverID to int, ICS API returns a string
-		serverIDInt, err := strconv.Atoi(serverID)
+		// Get the SSH Key ID from the Label
+		sshKey, err := client.FindSSHKeyByLabel(cmd.Context(), sshKeyName)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error converting server ID to int:", err)
-			return
+			return err
 		}
-		existingKeys, _ := getServerSSHKeys(serverIDInt)
 
-		// Convert serverIDInt to a list of IDs
+		// Get existing SSH keys assigned to the server
+		existingKeys, _ := client.GetServerSSHKeys(cmd.Context(), server.ID)
+
+		// Convert existing keys to a list of IDs
 		var sshKeyIDs []int
 		for _, key := range existingKeys {
 			sshKeyIDs = append(sshKeyIDs, key.ID)
@@ -61,22 +53,23 @@ var sshKeyAssignCmd = &cobra.Command{
 		sshKeyIDs = append(sshKeyIDs, sshKey.ID)
 
 		// Assign the SSH Key to the Server
-		assignKey, err := assignSSHKeys(serviceID, sshKeyIDs)
+		assignKey, err := client.AssignSSHKeys(cmd.Context(), server.ServiceID, sshKeyIDs)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error assigning SSH Key:", err)
-			return
+			return err
 		}
 
-		if assignKey {
-			fmt.Printf("%s\n", BlueHeading("Successfully assigned SSH Key to server. The SSH Key will be available after the next reinstall."))
+		message := "Successfully assigned SSH Key to server. The SSH Key will be available after the next reinstall."
+		if !assignKey {
+			message = "Failed to assign SSH Key to server. Please try again."
 		}
+		return printActionResult(newActionResult(server.ServiceID, "assign", assignKey, message))
 	},
 }
 
 func init() {
 	sshkeysCmd.AddCommand(sshKeyAssignCmd)
 
-	sshKeyAssignCmd.Flags().StringP("server", "s", "", "Service ID to assign the SSH Key to")
+	sshKeyAssignCmd.Flags().StringP("server", "s", "", "Server to assign the SSH Key to (service ID, hostname, friendly name or IP)")
 	sshKeyAssignCmd.MarkFlagRequired("server")
 
 }
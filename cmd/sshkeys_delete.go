@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -12,27 +11,27 @@ var sshKeyDeleteCmd = &cobra.Command{
 	Use:   "delete [sshKeyName]",
 	Short: "Delete an existing SSH Key",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get the SSH Key Name from the arguments
 		sshKeyName := args[0]
 
-		// Get the SSH Key from the Label
-		sshKey, err := getSSHKeyFromLabel(sshKeyName)
+		client, err := newAPIClient()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
-		// Update SSH Key Label
-		deleteKey, err := deleteSSHKey(sshKey.ID)
+		// Get the SSH Key from the Label
+		sshKey, err := client.FindSSHKeyByLabel(cmd.Context(), sshKeyName)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error deleting SSH Key:", err)
-			return
+			return err
 		}
 
-		if deleteKey {
-			fmt.Printf("%s\n", BlueHeading("Successfully deleted SSH Key"))
+		// Update SSH Key Label
+		if err := client.DeleteSSHKey(cmd.Context(), sshKey.ID); err != nil {
+			return fmt.Errorf("failed to delete SSH Key: %w", err)
 		}
+
+		return printActionResult(actionResult{Action: "delete", Success: true, Message: "Successfully deleted SSH Key"})
 	},
 }
 
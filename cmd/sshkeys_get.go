@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"time"
 
@@ -14,15 +13,23 @@ var getsshkeysCmd = &cobra.Command{
 	Use:   "get [sshKeyName]",
 	Short: "Get SSH key details by Name",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get the SSH Key Name from the arguments
 		sshKeyName := args[0]
 
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+
 		// Get the SSH Key from the Label
-		sshKey, err := getSSHKeyFromLabel(sshKeyName)
+		sshKey, err := client.FindSSHKeyByLabel(cmd.Context(), sshKeyName)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
+		}
+
+		if !isTableOutput() {
+			return printStructured(sshKey)
 		}
 
 		// Display SSH Key details
@@ -31,7 +38,7 @@ var getsshkeysCmd = &cobra.Command{
 		fmt.Printf("%s %s\n", BlueHeading("Updated At:"), WhiteText(time.Unix(sshKey.UpdatedAt, 0).Format("2006-01-02 15:04:05")))
 		fmt.Printf("%s %s\n", BlueHeading("Assigned to servers:"), WhiteText(strconv.Itoa(len(sshKey.AssignedServers))))
 		fmt.Printf("%s \n%s\n", BlueHeading("SSH Key:"), WhiteText(sshKey.Key))
-
+		return nil
 	},
 }
 
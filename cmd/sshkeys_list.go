@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/fatih/color"
@@ -15,18 +14,26 @@ var sshkeylistCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "Get a list of all SSH keys in your account",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+
 		// Make API call to get SSH keys
-		sshKeys, err := getSSHKeys()
+		sshKeys, err := client.ListSSHKeys(cmd.Context())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			return
+			return err
+		}
+
+		if !isTableOutput() {
+			return printStructured(sshKeys)
 		}
 
 		// If no ssh keys returned
 		if len(sshKeys) == 0 {
 			fmt.Println("No SSH Keys found in your account.")
-			return
+			return nil
 		}
 
 		headerFmt := color.New(color.FgBlue).SprintfFunc()
@@ -47,6 +54,7 @@ var sshkeylistCmd = &cobra.Command{
 		}
 
 		tbl.Print()
+		return nil
 	},
 }
 
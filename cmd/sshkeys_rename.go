@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -12,7 +11,7 @@ var sshKeyRenameCmd = &cobra.Command{
 	Use:   "rename [sshKeyName]",
 	Short: "Rename an existing SSH Key",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
 		// Get the SSH Key Name from the arguments
 		sshKeyName := args[0]
@@ -20,27 +19,26 @@ var sshKeyRenameCmd = &cobra.Command{
 		// Check if URL is provided
 		newName, _ := cmd.Flags().GetString("name")
 		if newName == "" {
-			fmt.Fprintln(os.Stderr, "Error: New SSH Key Name required with --name or -n flag")
-			return
+			return usageError("new SSH Key Name required with --name or -n flag")
 		}
 
-		// Get the SSH Key from the Label
-		sshKey, err := getSSHKeyFromLabel(sshKeyName)
+		client, err := newAPIClient()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
-		// Update SSH Key Label
-		setLabel, err := setSSHKeyLabel(sshKey.ID, newName)
+		// Get the SSH Key from the Label
+		sshKey, err := client.FindSSHKeyByLabel(cmd.Context(), sshKeyName)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error renaming SSH Key:", err)
-			return
+			return err
 		}
 
-		if setLabel {
-			fmt.Printf("%s\n", BlueHeading("Successfully renamed SSH Key"))
+		// Update SSH Key Label
+		if err := client.RenameSSHKey(cmd.Context(), sshKey.ID, newName); err != nil {
+			return fmt.Errorf("failed to rename SSH Key: %w", err)
 		}
+
+		return printActionResult(actionResult{Action: "rename", Success: true, Message: "Successfully renamed SSH Key"})
 	},
 }
 
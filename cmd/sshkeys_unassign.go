@@ -1,9 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/spf13/cobra"
 )
 
@@ -15,49 +12,47 @@ var sshKeyUnassignCmd = &cobra.Command{
   # Unassign an existing SSH Key from a Baremetal Server with Service ID 123456
   ics-cli sshkeys unassign my-ssh-key --server 123456`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
 		// Get the SSH Key Name from the arguments
 		sshKeyName := args[0]
 
-		// Check if Server ID is provided
-		serviceID, _ := cmd.Flags().GetString("server")
-		if serviceID == "" {
-			fmt.Fprintln(os.Stderr, "Error: Service ID must be provided with --server or -s flag")
-			return
+		// Check if a server is provided
+		query, _ := cmd.Flags().GetString("server")
+		if query == "" {
+			return usageError("server must be provided with --server or -s flag")
 		}
 
-		// Get the Server ID from Service ID
-		serverID, err := getServerIDFromServiceID(serviceID)
+		client, err := newAPIClient()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
-		// Get the SSH Key from the Label
-		sshKey, err := getSSHKeyFromLabel(sshKeyName)
+		// Resolve the server argument
+		server, err := client.ResolveServer(cmd.Context(), query)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
-		// Unassign the SSH Key to the Server
-		unassignKey, err := unassignSSHKey(serverID, sshKey.ID)
+		// Get the SSH Key from the Label
+		sshKey, err := client.FindSSHKeyByLabel(cmd.Context(), sshKeyName)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error unassigning SSH Key:", err)
-			return
+			return err
 		}
 
-		if unassignKey {
-			fmt.Printf("%s\n", BlueHeading("Successfully unassigned SSH Key from server. The SSH Key will be removed after the next reinstall."))
+		// Unassign the SSH Key to the Server
+		if err := client.UnassignSSHKey(cmd.Context(), server.ID, sshKey.ID); err != nil {
+			return err
 		}
+
+		return printActionResult(newActionResult(server.ServiceID, "unassign", true, "Successfully unassigned SSH Key from server. The SSH Key will be removed after the next reinstall."))
 	},
 }
 
 func init() {
 	sshkeysCmd.AddCommand(sshKeyUnassignCmd)
 
-	sshKeyUnassignCmd.Flags().StringP("server", "s", "", "Service ID to unassign the SSH Key from")
+	sshKeyUnassignCmd.Flags().StringP("server", "s", "", "Server to unassign the SSH Key from (service ID, hostname, friendly name or IP)")
 	sshKeyUnassignCmd.MarkFlagRequired("server")
 
 }
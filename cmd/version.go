@@ -16,8 +16,9 @@ var (
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number of ICS CLI",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s-%s (%s)", Version, Commit, BuildTime)
+		return nil
 	},
 }
 
@@ -1,4 +1,4 @@
-package cmd
+package icsapi
 
 // ServerResponse represents the server list API response.
 // This is returned when listing all servers.
@@ -44,30 +44,34 @@ type ServerDetail struct {
 	OperatingSystemPassword string `json:"operating_system_password"` // Password for OS login
 	DatacenterName          string `json:"datacenter"`                // Name of the datacenter where the server is located
 
-	// IPAddresses contains all IP addresses assigned to the server
-	IPAddresses []struct {
-		IPAddress string `json:"ipAddress"` // The IP address
-		IsPrimary bool   `json:"isPrimary"` // Whether this is the primary IP address
-		Gateway   string `json:"gateway"`   // Gateway address for this IP
-		Netmask   string `json:"netmask"`   // Network mask for this IP
-		VlanID    string `json:"vlanId"`    // VLAN ID if the IP is on a VLAN
-	} `json:"ip_addresses"`
+	IPAddresses        []ServerIPAddress   `json:"ip_addresses"`        // All IP addresses assigned to the server
+	NetworkPort        []ServerNetworkPort `json:"network_port"`        // Network port information
+	ProvisioningStatus ProvisioningStatus  `json:"provisioning_status"` // The server's provisioning state
+}
+
+// ServerIPAddress represents an IP address assigned to a server
+type ServerIPAddress struct {
+	IPAddress string `json:"ipAddress"` // The IP address
+	IsPrimary bool   `json:"isPrimary"` // Whether this is the primary IP address
+	Gateway   string `json:"gateway"`   // Gateway address for this IP
+	Netmask   string `json:"netmask"`   // Network mask for this IP
+	VlanID    string `json:"vlanId"`    // VLAN ID if the IP is on a VLAN
+}
 
-	// NetworkPort contains network port information
-	NetworkPort []struct {
-		ID         int    `json:"id"`         // Port identifier
-		PortNumber int    `json:"portNumber"` // Physical port number
-		MacAddress string `json:"macAddress"` // MAC address for this port
-		IPAddress  string `json:"ipAddress"`  // IP address assigned to this port
-		Speed      string `json:"speed"`      // Current port speed
-		MaxSpeed   string `json:"maxSpeed"`   // Maximum supported port speed
-	} `json:"network_port"`
+// ServerNetworkPort represents a network port of a server
+type ServerNetworkPort struct {
+	ID         int    `json:"id"`         // Port identifier
+	PortNumber int    `json:"portNumber"` // Physical port number
+	MacAddress string `json:"macAddress"` // MAC address for this port
+	IPAddress  string `json:"ipAddress"`  // IP address assigned to this port
+	Speed      string `json:"speed"`      // Current port speed
+	MaxSpeed   string `json:"maxSpeed"`   // Maximum supported port speed
+}
 
-	// ProvisioningStatus contains information about the server's provisioning state
-	ProvisioningStatus struct {
-		IsProvisioning bool   `json:"isProvisioning"` // Whether the server is currently being provisioned
-		StatusMessage  string `json:"statusMessage"`  // Human-readable status message
-	} `json:"provisioning_status"`
+// ProvisioningStatus contains information about the server's provisioning state
+type ProvisioningStatus struct {
+	IsProvisioning bool   `json:"isProvisioning"` // Whether the server is currently being provisioned
+	StatusMessage  string `json:"statusMessage"`  // Human-readable status message
 }
 
 // PowerStatusResponse represents the power status API response.
@@ -186,14 +190,6 @@ type Metadata struct {
 	Value       string `json:"value"`
 }
 
-// GroupedInventory represents inventory grouped by location and SKU
-type GroupedInventory struct {
-	LocationCode   string
-	SkuProductName string
-	Price          string
-	TotalQuantity  int
-}
-
 // AddonsResponse represents the response from the add-ons API
 type AddonsResponse struct {
 	StatusCode int        `json:"statusCode"`
@@ -0,0 +1,162 @@
+// Package icsapi is a Go client for the Ingenuity Cloud Services REST API.
+//
+// It exposes typed methods for servers, power, provisioning, remote access,
+// inventory, orders and SSH keys. Every method takes a context.Context so
+// callers can cancel or bound requests.
+package icsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultBaseURL is the production endpoint of the Ingenuity Cloud Services API
+const DefaultBaseURL = "https://api.ingenuitycloudservices.com/rest-api"
+
+// Client is an Ingenuity Cloud Services API client
+type Client struct {
+	BaseURL     string           // Base URL of the API, without a trailing slash
+	APIKey      string           // API key sent in the X-Api-Token header
+	Credentials CredentialSource // Source of the API key, used instead of APIKey if set
+	HTTPClient  *http.Client     // HTTP client used to perform requests
+	Retry       RetryPolicy      // Retry policy for idempotent requests
+}
+
+// NewClient returns a client for the production API using the given API key
+func NewClient(apiKey string) *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{},
+		Retry:      DefaultRetryPolicy,
+	}
+}
+
+// makeAPIRequest is a generic function to handle API calls with proper error handling.
+// The request body, if not nil, is encoded as JSON. The response is decoded into result.
+// GET and PUT requests are retried according to the client's retry policy.
+func (c *Client) makeAPIRequest(ctx context.Context, method string, timeout time.Duration, path string, body interface{}, result interface{}) error {
+	return c.request(ctx, method, timeout, path, body, result, method == "GET" || method == "PUT")
+}
+
+// request performs an API call, retrying transient failures if retryable is set.
+// The timeout applies to each attempt. A request rejected with 401 is sent again once
+// with a refreshed key if the API key comes from a credential source.
+func (c *Client) request(ctx context.Context, method string, timeout time.Duration, path string, body interface{}, result interface{}, retryable bool) error {
+	apiKey, err := c.apiKey(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if body != nil {
+		data, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error creating request body: %w", err)
+		}
+	}
+
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.attempt(ctx, method, timeout, path, apiKey, data, result)
+		if err == nil {
+			return nil
+		}
+
+		// The API rejects an invalid key before acting, so any request can be sent again
+		if errors.Is(err, ErrUnauthorized) && c.Credentials != nil && !refreshed {
+			refreshed = true
+			if apiKey, err = c.apiKey(ctx, apiKey); err != nil {
+				return err
+			}
+			attempt--
+			continue
+		}
+
+		var transient *transientError
+		if !retryable || !errors.As(err, &transient) || attempt >= c.Retry.MaxRetries {
+			return err
+		}
+
+		delay := c.Retry.backoff(attempt)
+		if retryAfter > 0 {
+			// Give up rather than retry earlier than the API asked for
+			if c.Retry.MaxDelay > 0 && retryAfter > c.Retry.MaxDelay {
+				return err
+			}
+			delay = retryAfter
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attempt performs a single API call. Failures worth retrying are wrapped in a transientError,
+// along with the delay requested by a Retry-After header.
+func (c *Client) attempt(ctx context.Context, method string, timeout time.Duration, path, apiKey string, data []byte, result interface{}) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var reqBody io.Reader
+	if data != nil {
+		reqBody = bytes.NewReader(data)
+	}
+
+	url := c.BaseURL + path
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Add("X-Api-Token", apiKey)
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, &transientError{fmt.Errorf("error connecting to API: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	// Read the response
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, &transientError{fmt.Errorf("error reading API response: %w", err)}
+	}
+
+	// Handle status codes
+	if resp.StatusCode != http.StatusOK {
+		apiErr := newAPIError(resp.StatusCode, method, url, respBody)
+
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return parseRetryAfter(resp.Header.Get("Retry-After")), &transientError{apiErr}
+		}
+
+		return 0, apiErr
+	}
+
+	// Parse the response
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return 0, fmt.Errorf("error parsing API response: %w", err)
+	}
+
+	return 0, nil
+}
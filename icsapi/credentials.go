@@ -0,0 +1,32 @@
+package icsapi
+
+import (
+	"context"
+)
+
+// CredentialSource provides the API key of a client, e.g. from a secret manager, instead
+// of a fixed APIKey. It must be safe for concurrent use.
+type CredentialSource interface {
+	// APIKey returns the API key to send. rejected is the key the API just rejected, or
+	// empty. A source caching keys should fetch a new one if its cached key was rejected.
+	APIKey(ctx context.Context, rejected string) (string, error)
+}
+
+// apiKey returns the API key for a request, from the credential source if there is one
+func (c *Client) apiKey(ctx context.Context, rejected string) (string, error) {
+	if c.Credentials == nil {
+		if c.APIKey == "" {
+			return "", ErrMissingAPIKey
+		}
+		return c.APIKey, nil
+	}
+
+	key, err := c.Credentials.APIKey(ctx, rejected)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return "", ErrMissingAPIKey
+	}
+	return key, nil
+}
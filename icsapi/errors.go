@@ -0,0 +1,165 @@
+package icsapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrMissingAPIKey is returned when a request is attempted without an API key
+	ErrMissingAPIKey = errors.New("no API key configured")
+
+	// ErrUnauthorized matches an APIError for a rejected API key
+	ErrUnauthorized = errors.New("API key is invalid or expired")
+
+	// ErrNotFound matches an APIError for a resource that does not exist
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrWaitTimeout is returned when the deadline of a wait expires before the
+	// server reaches the expected state
+	ErrWaitTimeout = errors.New("timed out waiting")
+)
+
+// APIError is returned when the API answers with an error status code.
+// Use errors.As to inspect it, or errors.Is with ErrUnauthorized and ErrNotFound.
+type APIError struct {
+	StatusCode int      // HTTP status code of the response
+	Message    string   // Message returned by the API
+	Details    []string // Validation details returned by the API, if any
+	Method     string   // HTTP method of the request
+	URL        string   // URL of the request
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	message := e.Message
+	switch {
+	case e.StatusCode == http.StatusUnauthorized && message == "":
+		message = ErrUnauthorized.Error()
+	case message == "":
+		message = fmt.Sprintf("API returned error status code: %d", e.StatusCode)
+	}
+
+	if len(e.Details) > 0 {
+		message += ": " + strings.Join(e.Details, "; ")
+	}
+
+	return fmt.Sprintf("%s (%d %s %s)", message, e.StatusCode, e.Method, e.URL)
+}
+
+// Is reports whether the error matches ErrUnauthorized or ErrNotFound
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// notFoundError is returned when a lookup done by the client, such as finding
+// a server by service ID, matches nothing. It matches ErrNotFound.
+type notFoundError struct {
+	message string
+}
+
+// Error implements the error interface
+func (e *notFoundError) Error() string {
+	return e.message
+}
+
+// Is reports whether the error matches ErrNotFound
+func (e *notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// waitError returns the error for a wait for the described state ended by ctx
+func waitError(ctx context.Context, state string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w for %s", ErrWaitTimeout, state)
+	}
+	return fmt.Errorf("stopped waiting for %s: %w", state, ctx.Err())
+}
+
+// errorEnvelope is the body the API returns with an error status code.
+// The message is a string, or a list of validation messages.
+type errorEnvelope struct {
+	StatusCode int             `json:"statusCode"`
+	Message    json.RawMessage `json:"message"`
+	Error      string          `json:"error"`
+	Errors     json.RawMessage `json:"errors"`
+}
+
+// newAPIError builds an APIError from an error response body
+func newAPIError(statusCode int, method, url string, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Method: method, URL: url}
+
+	var envelope errorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		// Not a JSON envelope, keep a short plain text body as the message
+		if text := strings.TrimSpace(string(body)); text != "" && len(text) < 200 && !strings.HasPrefix(text, "<") {
+			apiErr.Message = text
+		}
+		return apiErr
+	}
+
+	var message string
+	var messages []string
+	if json.Unmarshal(envelope.Message, &message) == nil {
+		apiErr.Message = message
+	} else if json.Unmarshal(envelope.Message, &messages) == nil && len(messages) > 0 {
+		// Validation failures list every problem in the message
+		apiErr.Message = envelope.Error
+		if apiErr.Message == "" {
+			apiErr.Message = "Validation failed"
+		}
+		apiErr.Details = messages
+	} else {
+		apiErr.Message = envelope.Error
+	}
+
+	apiErr.Details = append(apiErr.Details, flattenDetails(envelope.Errors)...)
+
+	return apiErr
+}
+
+// flattenDetails turns an "errors" field, either a list or a map of field names
+// to messages, into a list of messages
+func flattenDetails(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+
+	var fields map[string]interface{}
+	if json.Unmarshal(raw, &fields) != nil {
+		return nil
+	}
+
+	var details []string
+	for field, value := range fields {
+		switch v := value.(type) {
+		case string:
+			details = append(details, fmt.Sprintf("%s: %s", field, v))
+		case []interface{}:
+			for _, item := range v {
+				details = append(details, fmt.Sprintf("%s: %v", field, item))
+			}
+		default:
+			details = append(details, fmt.Sprintf("%s: %v", field, v))
+		}
+	}
+	sort.Strings(details)
+
+	return details
+}
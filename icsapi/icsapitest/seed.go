@@ -0,0 +1,147 @@
+package icsapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"gopkg.in/yaml.v3"
+)
+
+// Seed describes the initial state of a fake API
+type Seed struct {
+	Username  string                    `json:"username"`  // Username returned by /user/details
+	Servers   []SeedServer              `json:"servers"`   // Servers in the account
+	SSHKeys   []icsapi.SSHKey           `json:"ssh_keys"`  // SSH keys in the account
+	Inventory []icsapi.InventoryDetails `json:"inventory"` // Inventory available to order
+	Addons    icsapi.AddonTypes         `json:"addons"`    // Add-ons offered for every SKU and location
+	OSList    []icsapi.OS               `json:"os_list"`   // Operating systems available for reinstall
+}
+
+// SeedServer describes a server in a Seed
+type SeedServer struct {
+	icsapi.ServerDetail
+	ServerType string `json:"server_type"` // Type of server (e.g., "dedicated")
+	PoweredOn  bool   `json:"powered_on"`  // Initial power state
+	SSHKeyIDs  []int  `json:"ssh_key_ids"` // IDs of SSH keys assigned to the server
+}
+
+// LoadSeed reads a seed from a YAML or JSON file
+func LoadSeed(path string) (*Seed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading seed file: %w", err)
+	}
+
+	// Decode YAML (a superset of JSON) generically, then reuse the JSON tags
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing seed file: %w", err)
+	}
+
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing seed file: %w", err)
+	}
+
+	var seed Seed
+	if err := json.Unmarshal(jsonData, &seed); err != nil {
+		return nil, fmt.Errorf("error parsing seed file: %w", err)
+	}
+
+	return &seed, nil
+}
+
+// DefaultSeed returns a small fleet suitable for development
+func DefaultSeed() *Seed {
+	seed := &Seed{
+		Username: "mock-user",
+		SSHKeys: []icsapi.SSHKey{
+			{ID: 1, Label: "deploy", Key: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMockDeployKey deploy@example.com", CreatedAt: 1700000000, UpdatedAt: 1700000000},
+			{ID: 2, Label: "backup", Key: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMockBackupKey backup@example.com", CreatedAt: 1700000000, UpdatedAt: 1700000000},
+		},
+		OSList: []icsapi.OS{
+			{ID: "ubuntu-24-04", Name: "Ubuntu 24.04", Version: "24.04", Licenses: []string{""}},
+			{ID: "debian-12", Name: "Debian 12", Version: "12", Licenses: []string{""}},
+			{ID: "windows-2022", Name: "Windows Server 2022", Version: "2022", Licenses: []string{"WINDOWS"}},
+		},
+		Inventory: []icsapi.InventoryDetails{
+			{
+				SkuID: 1, Quantity: 12, AutoProvisionQty: 4, DatacenterID: 1, RegionID: 1, LocationCode: "NYC1",
+				CPUBrand: "AMD", CPUModel: "EPYC 4244P", CPUClockSpeedGhz: 3.8, CPUCores: 6, CPUCount: 1,
+				TotalNVMESizeGB: 960, TotalRAMGB: 32, NICSpeedMbps: 1000, Status: "active",
+				CurrencyCode: "USD", SkuProductName: "c1i.small", Price: "99.00",
+				Metadata: []icsapi.Metadata{{Name: "bandwidth", Description: "Included bandwidth", Value: "20TB"}},
+			},
+			{
+				SkuID: 2, Quantity: 3, AutoProvisionQty: 1, DatacenterID: 1, RegionID: 1, LocationCode: "NYC1",
+				CPUBrand: "AMD", CPUModel: "EPYC 9254", CPUClockSpeedGhz: 2.9, CPUCores: 24, CPUCount: 2,
+				TotalNVMESizeGB: 7680, TotalRAMGB: 384, RAIDEnabled: true, NICSpeedMbps: 10000, Status: "active",
+				CurrencyCode: "USD", SkuProductName: "c2i.large", Price: "649.00",
+			},
+			{
+				SkuID: 1, Quantity: 5, AutoProvisionQty: 2, DatacenterID: 2, RegionID: 2, LocationCode: "AMS1",
+				CPUBrand: "AMD", CPUModel: "EPYC 4244P", CPUClockSpeedGhz: 3.8, CPUCores: 6, CPUCount: 1,
+				TotalNVMESizeGB: 960, TotalRAMGB: 32, NICSpeedMbps: 1000, Status: "active",
+				CurrencyCode: "USD", SkuProductName: "c1i.small", Price: "109.00",
+			},
+			{
+				SkuID: 3, Quantity: 0, DatacenterID: 2, RegionID: 2, LocationCode: "AMS1",
+				CPUBrand: "Intel", CPUModel: "Xeon E-2386G", CPUClockSpeedGhz: 3.5, CPUCores: 6, CPUCount: 1,
+				TotalSSDSizeGB: 960, TotalHDDSizeGB: 8000, TotalRAMGB: 64, RAIDEnabled: true, NICSpeedMbps: 1000, Status: "active",
+				CurrencyCode: "USD", SkuProductName: "s1i.storage", Price: "189.00",
+			},
+		},
+		Addons: icsapi.AddonTypes{
+			OperatingSystems: icsapi.OperatingSystemsSection{
+				Name:     "Operating Systems",
+				Required: "true",
+				Products: []icsapi.OSProduct{
+					{Name: "Ubuntu 24.04", OSType: "linux", ProductCode: "UBUNTU_24_04", Price: 0},
+					{Name: "Debian 12", OSType: "linux", ProductCode: "DEBIAN_12", Price: 0},
+					{Name: "Windows Server 2022 Standard", OSType: "windows", ProductCode: "WIN_2022_STD", PricePerCore: 2.5},
+				},
+			},
+			Licenses: icsapi.LicenseSection{
+				Name:     "Software Licenses",
+				Products: []icsapi.LicenseProduct{{Name: "cPanel 100 Accounts", ProductCode: "CPANEL100", Price: 45}},
+			},
+			SupportLevels: icsapi.SupportSection{
+				Name: "Support Levels",
+				Products: []icsapi.SupportProduct{
+					{Name: "Basic Support", Description: "Business hours support", ProductCode: "BASICSUP", Price: 0},
+					{Name: "Premium Support", Description: "24/7 support with 1h response", ProductCode: "PREMSUP", Price: 99},
+				},
+			},
+		},
+	}
+
+	servers := []struct {
+		hostname, friendlyName, datacenter, ip, os string
+		poweredOn                                  bool
+		keys                                       []int
+	}{
+		{"web-01.example.com", "web-01", "NYC1", "192.0.2.11", "Ubuntu 24.04", true, []int{1}},
+		{"web-02.example.com", "web-02", "NYC1", "192.0.2.12", "Ubuntu 24.04", true, []int{1}},
+		{"db-01.example.com", "db-01", "NYC1", "192.0.2.21", "Debian 12", true, []int{1, 2}},
+		{"build-01.example.com", "", "AMS1", "198.51.100.31", "Debian 12", false, nil},
+	}
+
+	for i, s := range servers {
+		server := SeedServer{ServerType: "dedicated", PoweredOn: s.poweredOn, SSHKeyIDs: s.keys}
+		server.ServerID = 1001 + i
+		server.ServiceID = 500001 + i
+		server.Hostname = s.hostname
+		server.FriendlyName = s.friendlyName
+		server.DatacenterName = s.datacenter
+		server.PublicIP = s.ip
+		server.MacAddress = fmt.Sprintf("00:25:90:00:00:%02x", i+1)
+		server.OperatingSystemName = s.os
+		server.OperatingSystemUsername = "root"
+		server.OperatingSystemPassword = "mock-password"
+		seed.Servers = append(seed.Servers, server)
+	}
+
+	return seed
+}
@@ -0,0 +1,715 @@
+// Package icsapitest provides an in-memory fake of the Ingenuity Cloud Services API.
+//
+// The fake serves the endpoints consumed by the icsapi client and returns the
+// same response envelopes as the real API. Power, reinstall, order and SSH key
+// operations change its state, so complete workflows can be exercised offline.
+package icsapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+)
+
+// Options configures the behaviour of a fake API server
+type Options struct {
+	APIKey         string        // If set, only this API key is accepted. Otherwise any non-empty key is.
+	PowerDelay     time.Duration // Time a power transition takes to complete
+	ProvisionDelay time.Duration // Time a reinstall or a new order takes to provision
+}
+
+// Server is an in-memory fake of the ICS API. It implements http.Handler.
+type Server struct {
+	mu   sync.Mutex
+	opts Options
+	mux  *http.ServeMux
+	now  func() time.Time
+
+	username  string
+	servers   []*fakeServer
+	sshKeys   []*icsapi.SSHKey
+	inventory []icsapi.InventoryDetails
+	addons    icsapi.AddonTypes
+	osList    []icsapi.OS
+
+	nextServerID  int
+	nextServiceID int
+	nextSSHKeyID  int
+}
+
+// fakeServer holds the mutable state of a server
+type fakeServer struct {
+	detail     icsapi.ServerDetail
+	serverType string
+	sshKeyIDs  []int
+	pxeURL     string
+
+	poweredOn    bool
+	powerPending bool      // A power transition is in progress
+	powerTarget  bool      // Power state reached when the transition completes
+	powerAt      time.Time // When the power transition completes
+
+	provisionStart time.Time // When the current provisioning started
+	provisionEnd   time.Time // When the current provisioning completes
+}
+
+// provisioningStages are the status messages reported while a server provisions
+var provisioningStages = []string{
+	"Queued for provisioning",
+	"Installing operating system",
+	"Configuring network",
+	"Finalizing installation",
+}
+
+// NewServer returns a fake API server populated from seed.
+// A nil seed starts with DefaultSeed.
+func NewServer(seed *Seed, opts Options) *Server {
+	if seed == nil {
+		seed = DefaultSeed()
+	}
+
+	s := &Server{
+		opts:          opts,
+		now:           time.Now,
+		username:      seed.Username,
+		inventory:     append([]icsapi.InventoryDetails(nil), seed.Inventory...),
+		addons:        seed.Addons,
+		osList:        append([]icsapi.OS(nil), seed.OSList...),
+		nextServerID:  1,
+		nextServiceID: 1,
+		nextSSHKeyID:  1,
+	}
+
+	for _, seedServer := range seed.Servers {
+		server := &fakeServer{
+			detail:     seedServer.ServerDetail,
+			serverType: seedServer.ServerType,
+			sshKeyIDs:  append([]int(nil), seedServer.SSHKeyIDs...),
+			poweredOn:  seedServer.PoweredOn,
+		}
+		if server.detail.ServerID == 0 {
+			server.detail.ServerID = s.nextServerID
+		}
+		if server.detail.ServiceID == 0 {
+			server.detail.ServiceID = s.nextServiceID
+		}
+		if len(server.detail.IPAddresses) == 0 && server.detail.PublicIP != "" {
+			server.detail.IPAddresses = []icsapi.ServerIPAddress{{IPAddress: server.detail.PublicIP, IsPrimary: true}}
+		}
+		s.nextServerID = max(s.nextServerID, server.detail.ServerID+1)
+		s.nextServiceID = max(s.nextServiceID, server.detail.ServiceID+1)
+		s.servers = append(s.servers, server)
+	}
+
+	for _, seedKey := range seed.SSHKeys {
+		key := seedKey
+		key.AssignedServers = nil
+		if key.ID == 0 {
+			key.ID = s.nextSSHKeyID
+		}
+		s.nextSSHKeyID = max(s.nextSSHKeyID, key.ID+1)
+		s.sshKeys = append(s.sshKeys, &key)
+	}
+
+	s.routes()
+
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Remote access links are opened in a browser, without an API key
+	if strings.HasPrefix(r.URL.Path, "/mock-console/") {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "Mock remote console for %s\n", strings.TrimPrefix(r.URL.Path, "/mock-console/"))
+		return
+	}
+
+	apiKey := r.Header.Get("X-Api-Token")
+	if apiKey == "" || (s.opts.APIKey != "" && apiKey != s.opts.APIKey) {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// routes registers the API endpoints
+func (s *Server) routes() {
+	s.mux = http.NewServeMux()
+
+	s.mux.HandleFunc("GET /user/details", s.handleUserDetails)
+
+	s.mux.HandleFunc("GET /servers", s.handleListServers)
+	s.mux.HandleFunc("GET /servers/{id}", s.withServer(s.handleGetServer))
+	s.mux.HandleFunc("PUT /servers/{id}/friendly-name", s.withServer(s.handleFriendlyName))
+	s.mux.HandleFunc("PUT /servers/{id}/set-pxe", s.withServer(s.handleSetPXE))
+	s.mux.HandleFunc("GET /servers/{id}/ssh-keys", s.withServer(s.handleServerSSHKeys))
+	s.mux.HandleFunc("PATCH /servers/{id}/ssh-keys/assign", s.withServer(s.handleAssignSSHKeys))
+	s.mux.HandleFunc("PATCH /servers/{id}/ssh-keys/un-assign", s.withServer(s.handleUnassignSSHKeys))
+
+	s.mux.HandleFunc("GET /servers/{id}/power/status", s.withServer(s.handlePowerStatus))
+	s.mux.HandleFunc("POST /servers/{id}/power/on", s.withServer(s.handlePowerOn))
+	s.mux.HandleFunc("POST /servers/{id}/power/off", s.withServer(s.handlePowerOff))
+	s.mux.HandleFunc("POST /servers/{id}/power/reboot", s.withServer(s.handleReboot))
+	s.mux.HandleFunc("POST /servers/{id}/recovery/reboot", s.withServer(s.handleReboot))
+
+	s.mux.HandleFunc("POST /servers/{id}/remote-access/{kind}", s.withServer(s.handleRemoteAccess))
+
+	s.mux.HandleFunc("GET /servers/{id}/provision/os-list", s.withServer(s.handleOSList))
+	s.mux.HandleFunc("POST /servers/{id}/provision/reload-os", s.withServer(s.handleReloadOS))
+
+	s.mux.HandleFunc("GET /server-orders/inventory", s.handleInventory)
+	s.mux.HandleFunc("GET /server-orders/list-addons", s.handleAddons)
+	s.mux.HandleFunc("POST /server-orders/order", s.handleOrder)
+
+	s.mux.HandleFunc("GET /ssh-keys", s.handleListSSHKeys)
+	s.mux.HandleFunc("POST /ssh-keys", s.handleAddSSHKey)
+	s.mux.HandleFunc("PUT /ssh-keys/{id}", s.withSSHKey(s.handleRenameSSHKey))
+	s.mux.HandleFunc("DELETE /ssh-keys/{id}", s.withSSHKey(s.handleDeleteSSHKey))
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Cannot %s %s", r.Method, r.URL.Path))
+	})
+}
+
+// withServer resolves the {id} path value to a server before calling next.
+// Both server IDs and service IDs are accepted.
+func (s *Server) withServer(next func(http.ResponseWriter, *http.Request, *fakeServer)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err == nil {
+			for _, server := range s.servers {
+				if server.detail.ServerID == id || server.detail.ServiceID == id {
+					s.refresh(server)
+					next(w, r, server)
+					return
+				}
+			}
+		}
+
+		writeError(w, http.StatusNotFound, "Server not found")
+	}
+}
+
+// withSSHKey resolves the {id} path value to an SSH key before calling next
+func (s *Server) withSSHKey(next func(http.ResponseWriter, *http.Request, *icsapi.SSHKey)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err == nil {
+			for _, key := range s.sshKeys {
+				if key.ID == id {
+					next(w, r, key)
+					return
+				}
+			}
+		}
+
+		writeError(w, http.StatusNotFound, "SSH key not found")
+	}
+}
+
+// refresh completes power transitions and provisioning that are due
+func (s *Server) refresh(server *fakeServer) {
+	now := s.now()
+
+	if server.powerPending && !now.Before(server.powerAt) {
+		server.poweredOn = server.powerTarget
+		server.powerPending = false
+	}
+
+	status := &server.detail.ProvisioningStatus
+	if !status.IsProvisioning {
+		return
+	}
+
+	if !now.Before(server.provisionEnd) {
+		status.IsProvisioning = false
+		status.StatusMessage = "Provisioning complete"
+		server.poweredOn = true
+		server.powerPending = false
+		return
+	}
+
+	// Report a stage proportional to the elapsed provisioning time
+	total := server.provisionEnd.Sub(server.provisionStart)
+	stage := int(float64(len(provisioningStages)) * float64(now.Sub(server.provisionStart)) / float64(total))
+	status.StatusMessage = provisioningStages[min(stage, len(provisioningStages)-1)]
+}
+
+// setPower starts a transition to the given power state
+func (s *Server) setPower(server *fakeServer, on bool) {
+	server.powerPending = true
+	server.powerTarget = on
+	server.powerAt = s.now().Add(s.opts.PowerDelay)
+	s.refresh(server)
+}
+
+// startProvisioning marks a server as provisioning for the configured delay
+func (s *Server) startProvisioning(server *fakeServer) {
+	now := s.now()
+	server.poweredOn = false
+	server.powerPending = false
+	server.provisionStart = now
+	server.provisionEnd = now.Add(s.opts.ProvisionDelay)
+	server.detail.ProvisioningStatus = icsapi.ProvisioningStatus{IsProvisioning: true, StatusMessage: provisioningStages[0]}
+	s.refresh(server)
+}
+
+// summary returns the list representation of a server
+func (server *fakeServer) summary() icsapi.Server {
+	return icsapi.Server{
+		ID:             strconv.Itoa(server.detail.ServerID),
+		Hostname:       server.detail.Hostname,
+		MacAddress:     server.detail.MacAddress,
+		PublicIP:       server.detail.PublicIP,
+		ServiceID:      server.detail.ServiceID,
+		DatacenterName: server.detail.DatacenterName,
+		FriendlyName:   server.detail.FriendlyName,
+		ServerType:     server.serverType,
+	}
+}
+
+func (s *Server) handleUserDetails(w http.ResponseWriter, r *http.Request) {
+	var data icsapi.UserResponse
+	data.Data.UserProfile.Username = s.username
+	writeData(w, data.Data)
+}
+
+func (s *Server) handleListServers(w http.ResponseWriter, r *http.Request) {
+	servers := make([]icsapi.Server, 0, len(s.servers))
+	for _, server := range s.servers {
+		s.refresh(server)
+		servers = append(servers, server.summary())
+	}
+	writeData(w, servers)
+}
+
+func (s *Server) handleGetServer(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	writeData(w, server.detail)
+}
+
+func (s *Server) handleFriendlyName(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	var body struct {
+		FriendlyName string `json:"friendly_name"`
+	}
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	server.detail.FriendlyName = body.FriendlyName
+	writeSuccess(w)
+}
+
+func (s *Server) handleSetPXE(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	var body struct {
+		URL string `json:"pxe_script_url"`
+	}
+	if !readBody(w, r, &body) {
+		return
+	}
+	if !strings.HasPrefix(body.URL, "http://") && !strings.HasPrefix(body.URL, "https://") {
+		writeError(w, http.StatusBadRequest, "pxe_script_url must be an http or https URL")
+		return
+	}
+
+	// Setting a PXE URL reboots the server into it
+	server.pxeURL = body.URL
+	server.poweredOn = false
+	s.setPower(server, true)
+	writeSuccess(w)
+}
+
+func (s *Server) handleServerSSHKeys(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	keys := make([]icsapi.AssignedSSHKey, 0, len(server.sshKeyIDs))
+	for _, id := range server.sshKeyIDs {
+		if key := s.findSSHKey(id); key != nil {
+			keys = append(keys, icsapi.AssignedSSHKey{ID: key.ID, Label: key.Label, Key: key.Key, CreatedAt: key.CreatedAt, UpdatedAt: key.UpdatedAt})
+		}
+	}
+	writeData(w, keys)
+}
+
+func (s *Server) handleAssignSSHKeys(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	var body struct {
+		SSHKeyIDs []int `json:"ssh_key_ids"`
+	}
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	for _, id := range body.SSHKeyIDs {
+		if s.findSSHKey(id) == nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("SSH key %d does not exist", id))
+			return
+		}
+	}
+
+	server.sshKeyIDs = uniqueInts(body.SSHKeyIDs)
+	writeData(w, true)
+}
+
+func (s *Server) handleUnassignSSHKeys(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	var body struct {
+		SSHKeyIDs []int `json:"ssh_key_ids"`
+	}
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	remaining := server.sshKeyIDs[:0]
+	for _, id := range server.sshKeyIDs {
+		if !slices.Contains(body.SSHKeyIDs, id) {
+			remaining = append(remaining, id)
+		}
+	}
+	server.sshKeyIDs = remaining
+	writeData(w, []icsapi.SSHKey{})
+}
+
+func (s *Server) handlePowerStatus(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	writeData(w, map[string]bool{"is_powered_on": server.poweredOn})
+}
+
+func (s *Server) handlePowerOn(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	if server.detail.ProvisioningStatus.IsProvisioning {
+		writeError(w, http.StatusConflict, "Server is provisioning")
+		return
+	}
+	s.setPower(server, true)
+	writeSuccess(w)
+}
+
+func (s *Server) handlePowerOff(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	if server.detail.ProvisioningStatus.IsProvisioning {
+		writeError(w, http.StatusConflict, "Server is provisioning")
+		return
+	}
+	s.setPower(server, false)
+	writeSuccess(w)
+}
+
+func (s *Server) handleReboot(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	if server.detail.ProvisioningStatus.IsProvisioning {
+		writeError(w, http.StatusConflict, "Server is provisioning")
+		return
+	}
+
+	// A reboot goes off immediately and comes back on after the power delay
+	server.poweredOn = false
+	s.setPower(server, true)
+	writeSuccess(w)
+}
+
+func (s *Server) handleRemoteAccess(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	kind := r.PathValue("kind")
+	if kind != "ikvm" && kind != "sol" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Cannot %s %s", r.Method, r.URL.Path))
+		return
+	}
+
+	redirect := fmt.Sprintf("http://%s/mock-console/%s/%d", r.Host, kind, server.detail.ServerID)
+	writeData(w, map[string]string{"redirect": redirect})
+}
+
+func (s *Server) handleOSList(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	writeData(w, map[string][]icsapi.OS{"osList": s.osList})
+}
+
+func (s *Server) handleReloadOS(w http.ResponseWriter, r *http.Request, server *fakeServer) {
+	var body struct {
+		ImageID string `json:"os_image_id"`
+		Reason  string `json:"reason"`
+	}
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	if server.detail.ProvisioningStatus.IsProvisioning {
+		writeError(w, http.StatusConflict, "Server is already provisioning")
+		return
+	}
+
+	for _, os := range s.osList {
+		if os.ID == body.ImageID {
+			server.detail.OperatingSystemID = os.ID
+			server.detail.OperatingSystemName = os.Name
+			s.startProvisioning(server)
+			writeSuccess(w)
+			return
+		}
+	}
+
+	writeError(w, http.StatusBadRequest, fmt.Sprintf("Operating system %q is not available for this server", body.ImageID))
+}
+
+func (s *Server) handleInventory(w http.ResponseWriter, r *http.Request) {
+	writeData(w, s.inventory)
+}
+
+func (s *Server) handleAddons(w http.ResponseWriter, r *http.Request) {
+	sku := r.URL.Query().Get("sku_product_name")
+	location := r.URL.Query().Get("location_code")
+
+	if len(s.inventoryRows(sku, location)) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("SKU %q is not offered in %q", sku, location))
+		return
+	}
+
+	writeData(w, s.addons)
+}
+
+func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request) {
+	var order icsapi.OrderRequest
+	if !readBody(w, r, &order) {
+		return
+	}
+
+	// Report every validation problem at once, like the real API does
+	var problems []string
+	if order.Quantity <= 0 {
+		problems = append(problems, "quantity must be at least 1")
+	}
+
+	rows := s.inventoryRows(order.SKUProductName, order.LocationCode)
+	available := 0
+	for _, row := range rows {
+		available += row.Quantity
+	}
+	if len(rows) == 0 {
+		problems = append(problems, fmt.Sprintf("SKU %q is not offered in %q", order.SKUProductName, order.LocationCode))
+	} else if available < order.Quantity {
+		problems = append(problems, fmt.Sprintf("insufficient inventory: %d available, %d requested", available, order.Quantity))
+	}
+
+	var osProduct *icsapi.OSProduct
+	for i, product := range s.addons.OperatingSystems.Products {
+		if product.ProductCode == order.OperatingSystemProductCode {
+			osProduct = &s.addons.OperatingSystems.Products[i]
+		}
+	}
+	if osProduct == nil {
+		problems = append(problems, fmt.Sprintf("operating system %q is not available", order.OperatingSystemProductCode))
+	}
+
+	for _, id := range order.SSHKeyIDs {
+		if s.findSSHKey(id) == nil {
+			problems = append(problems, fmt.Sprintf("SSH key %d does not exist", id))
+		}
+	}
+
+	if len(problems) > 0 {
+		writeValidationError(w, problems)
+		return
+	}
+
+	// Take the servers from the rows in order
+	remaining := order.Quantity
+	for _, row := range rows {
+		taken := min(row.Quantity, remaining)
+		row.Quantity -= taken
+		row.AutoProvisionQty = min(row.AutoProvisionQty, row.Quantity)
+		remaining -= taken
+	}
+
+	serviceIDs := make([]int, 0, order.Quantity)
+	for i := 0; i < order.Quantity; i++ {
+		server := &fakeServer{serverType: "dedicated", sshKeyIDs: uniqueInts(order.SSHKeyIDs)}
+		server.detail = icsapi.ServerDetail{
+			ServerID:                s.nextServerID,
+			ServiceID:               s.nextServiceID,
+			Hostname:                fmt.Sprintf("%s-%d.mock.local", strings.ReplaceAll(order.SKUProductName, ".", "-"), s.nextServiceID),
+			PublicIP:                fmt.Sprintf("203.0.113.%d", s.nextServerID%254+1),
+			MacAddress:              fmt.Sprintf("00:25:90:%02x:%02x:%02x", s.nextServerID>>16&0xff, s.nextServerID>>8&0xff, s.nextServerID&0xff),
+			DatacenterName:          order.LocationCode,
+			OperatingSystemID:       order.OperatingSystemProductCode,
+			OperatingSystemName:     osProduct.Name,
+			OperatingSystemUsername: "root",
+			OperatingSystemPassword: fmt.Sprintf("mock-%d", s.nextServiceID),
+		}
+		server.detail.IPAddresses = []icsapi.ServerIPAddress{{IPAddress: server.detail.PublicIP, IsPrimary: true}}
+
+		s.startProvisioning(server)
+		s.servers = append(s.servers, server)
+		serviceIDs = append(serviceIDs, s.nextServiceID)
+		s.nextServerID++
+		s.nextServiceID++
+	}
+
+	writeData(w, map[string][]int{"order_service_ids": serviceIDs})
+}
+
+func (s *Server) handleListSSHKeys(w http.ResponseWriter, r *http.Request) {
+	keys := make([]icsapi.SSHKey, 0, len(s.sshKeys))
+	for _, key := range s.sshKeys {
+		k := *key
+		k.AssignedServers = []icsapi.SSHServer{}
+		for _, server := range s.servers {
+			if slices.Contains(server.sshKeyIDs, key.ID) {
+				k.AssignedServers = append(k.AssignedServers, icsapi.SSHServer{
+					ServerID:       strconv.Itoa(server.detail.ServerID),
+					ServiceID:      server.detail.ServiceID,
+					Hostname:       server.detail.Hostname,
+					DatacenterName: server.detail.DatacenterName,
+				})
+			}
+		}
+		keys = append(keys, k)
+	}
+	writeData(w, keys)
+}
+
+func (s *Server) handleAddSSHKey(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Label     string `json:"label"`
+		PublicKey string `json:"public_key"`
+	}
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	if body.Label == "" || body.PublicKey == "" {
+		writeError(w, http.StatusBadRequest, "label and public_key are required")
+		return
+	}
+	for _, key := range s.sshKeys {
+		if key.Label == body.Label {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("An SSH key labelled %q already exists", body.Label))
+			return
+		}
+	}
+
+	now := s.now().Unix()
+	key := &icsapi.SSHKey{ID: s.nextSSHKeyID, Label: body.Label, Key: body.PublicKey, CreatedAt: now, UpdatedAt: now}
+	s.sshKeys = append(s.sshKeys, key)
+	s.nextSSHKeyID++
+
+	writeData(w, map[string]int{"id": key.ID})
+}
+
+func (s *Server) handleRenameSSHKey(w http.ResponseWriter, r *http.Request, key *icsapi.SSHKey) {
+	var body struct {
+		Label string `json:"label"`
+	}
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	if body.Label == "" {
+		writeError(w, http.StatusBadRequest, "label is required")
+		return
+	}
+
+	key.Label = body.Label
+	key.UpdatedAt = s.now().Unix()
+	writeSuccess(w)
+}
+
+func (s *Server) handleDeleteSSHKey(w http.ResponseWriter, r *http.Request, key *icsapi.SSHKey) {
+	for i, k := range s.sshKeys {
+		if k == key {
+			s.sshKeys = append(s.sshKeys[:i], s.sshKeys[i+1:]...)
+			break
+		}
+	}
+
+	for _, server := range s.servers {
+		remaining := server.sshKeyIDs[:0]
+		for _, id := range server.sshKeyIDs {
+			if id != key.ID {
+				remaining = append(remaining, id)
+			}
+		}
+		server.sshKeyIDs = remaining
+	}
+
+	writeSuccess(w)
+}
+
+// findSSHKey returns the SSH key with the given ID, or nil
+func (s *Server) findSSHKey(id int) *icsapi.SSHKey {
+	for _, key := range s.sshKeys {
+		if key.ID == id {
+			return key
+		}
+	}
+	return nil
+}
+
+// inventoryRows returns the inventory rows for a SKU in a location. The API may list
+// the stock of a SKU in a location over several rows.
+func (s *Server) inventoryRows(sku, location string) []*icsapi.InventoryDetails {
+	var rows []*icsapi.InventoryDetails
+	for i, item := range s.inventory {
+		if strings.EqualFold(item.SkuProductName, sku) && strings.EqualFold(item.LocationCode, location) {
+			rows = append(rows, &s.inventory[i])
+		}
+	}
+	return rows
+}
+
+// readBody decodes a JSON request body, answering 400 if it is invalid
+func readBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeData writes a successful response envelope
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"statusCode": http.StatusOK,
+		"message":    "OK",
+		"data":       data,
+	})
+}
+
+// writeSuccess writes the envelope used by management operations
+func writeSuccess(w http.ResponseWriter) {
+	writeData(w, map[string]bool{"success": true})
+}
+
+// writeError writes an error response envelope
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"statusCode": status,
+		"message":    message,
+	})
+}
+
+// writeValidationError writes the envelope used for rejected request bodies
+func writeValidationError(w http.ResponseWriter, problems []string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"statusCode": http.StatusBadRequest,
+		"message":    problems,
+		"error":      "Bad Request",
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func uniqueInts(values []int) []int {
+	unique := []int{}
+	for _, v := range values {
+		if !slices.Contains(unique, v) {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,193 @@
+package icsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Inventory retrieves the server inventory available to order
+func (c *Client) Inventory(ctx context.Context) ([]InventoryDetails, error) {
+	var response InventoryResponse
+
+	err := c.makeAPIRequest(ctx, "GET", 60*time.Second, "/server-orders/inventory", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
+	}
+
+	return response.Data, nil
+}
+
+// Addons retrieves available add-ons for a server type in a datacenter
+func (c *Client) Addons(ctx context.Context, sku, datacenter string) (*AddonTypes, error) {
+	var response AddonsResponse
+
+	query := url.Values{}
+	query.Set("sku_product_name", sku)
+	query.Set("location_code", datacenter)
+
+	err := c.makeAPIRequest(ctx, "GET", 60*time.Second, "/server-orders/list-addons?"+query.Encode(), nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get add-ons: %w", err)
+	}
+
+	return &response.Data, nil
+}
+
+// PlaceOrder places a server order and returns the service IDs it created
+func (c *Client) PlaceOrder(ctx context.Context, order OrderRequest) ([]int, error) {
+	var response OrderResponse
+
+	err := c.makeAPIRequest(ctx, "POST", 60*time.Second, "/server-orders/order", order, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	return response.Data.OrderServiceIDs, nil
+}
+
+// WaitForServices polls the server list every interval until a server exists for each of
+// the service IDs, which happens some time after an order is placed. The servers are
+// returned in the order of serviceIDs. Bound the wait with a context deadline: an error
+// matching ErrWaitTimeout is returned when it expires.
+func (c *Client) WaitForServices(ctx context.Context, serviceIDs []int, interval time.Duration) ([]Server, error) {
+	for {
+		servers, err := c.ListServers(ctx)
+		if errors.Is(err, ErrUnauthorized) {
+			return nil, err
+		}
+
+		if err == nil {
+			byServiceID := make(map[int]Server, len(servers))
+			for _, server := range servers {
+				byServiceID[server.ServiceID] = server
+			}
+
+			found := make([]Server, 0, len(serviceIDs))
+			for _, id := range serviceIDs {
+				if server, ok := byServiceID[id]; ok {
+					found = append(found, server)
+				}
+			}
+			if len(found) == len(serviceIDs) {
+				return found, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, waitError(ctx, "the ordered servers to be created")
+		case <-time.After(interval):
+		}
+	}
+}
+
+// OrderCatalog is what an order is checked and priced against: the inventory item of
+// its SKU in its location and the add-ons offered for them
+type OrderCatalog struct {
+	Item   *InventoryDetails // Inventory item with the stock of every row for the SKU and location, nil if not offered
+	Addons *AddonTypes       // Add-ons offered, nil if the SKU is not offered in the location
+}
+
+// GetOrderCatalog fetches the inventory item and add-ons for the SKU and location of an order.
+// The stock of every inventory row for them is summed.
+func (c *Client) GetOrderCatalog(ctx context.Context, order OrderRequest) (*OrderCatalog, error) {
+	inventory, err := c.Inventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The inventory may list a SKU in a location over several rows: the first one gives
+	// the price and hardware, and the stock is the sum of all of them
+	catalog := &OrderCatalog{}
+	for _, row := range inventory {
+		if !strings.EqualFold(row.SkuProductName, order.SKUProductName) || !strings.EqualFold(row.LocationCode, order.LocationCode) {
+			continue
+		}
+		if catalog.Item == nil {
+			item := row
+			catalog.Item = &item
+			continue
+		}
+		catalog.Item.Quantity += row.Quantity
+		catalog.Item.AutoProvisionQty += row.AutoProvisionQty
+	}
+
+	// Without a SKU in the location there are no add-ons to fetch
+	if catalog.Item == nil {
+		return catalog, nil
+	}
+
+	catalog.Addons, err = c.Addons(ctx, catalog.Item.SkuProductName, catalog.Item.LocationCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return catalog, nil
+}
+
+// ValidateOrder checks an order against the inventory and the add-ons offered for its
+// SKU and location, and returns every problem found. An empty result means the order
+// is expected to be accepted. SSH key IDs are not checked.
+func (c *Client) ValidateOrder(ctx context.Context, order OrderRequest) ([]string, error) {
+	catalog, err := c.GetOrderCatalog(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return catalog.Validate(order), nil
+}
+
+// Validate returns every problem found checking an order against the catalog
+func (cat *OrderCatalog) Validate(order OrderRequest) []string {
+	if cat.Item == nil {
+		return []string{fmt.Sprintf("SKU %q is not offered in %s", order.SKUProductName, order.LocationCode)}
+	}
+
+	var problems []string
+	if cat.Item.Quantity < order.Quantity {
+		problems = append(problems, fmt.Sprintf("insufficient inventory for %s in %s: %d available, %d requested", cat.Item.SkuProductName, cat.Item.LocationCode, cat.Item.Quantity, order.Quantity))
+	}
+
+	var osCodes []string
+	for _, product := range cat.Addons.OperatingSystems.Products {
+		osCodes = append(osCodes, product.ProductCode)
+	}
+	if !slices.Contains(osCodes, order.OperatingSystemProductCode) {
+		problems = append(problems, unavailableProduct("operating system", order.OperatingSystemProductCode, osCodes))
+	}
+
+	if order.LicenseProductCode != "" {
+		var licenseCodes []string
+		for _, product := range cat.Addons.Licenses.Products {
+			licenseCodes = append(licenseCodes, product.ProductCode)
+		}
+		if !slices.Contains(licenseCodes, order.LicenseProductCode) {
+			problems = append(problems, unavailableProduct("license", order.LicenseProductCode, licenseCodes))
+		}
+	}
+
+	if order.SupportLevelProductCode != "" {
+		var supportCodes []string
+		for _, product := range cat.Addons.SupportLevels.Products {
+			supportCodes = append(supportCodes, product.ProductCode)
+		}
+		if !slices.Contains(supportCodes, order.SupportLevelProductCode) {
+			problems = append(problems, unavailableProduct("support level", order.SupportLevelProductCode, supportCodes))
+		}
+	}
+
+	return problems
+}
+
+// unavailableProduct describes a product code that is not offered, listing the available ones
+func unavailableProduct(kind, code string, available []string) string {
+	if len(available) == 0 {
+		return fmt.Sprintf("%s %q is not available: none are offered", kind, code)
+	}
+	return fmt.Sprintf("%s %q is not available (available: %s)", kind, code, strings.Join(available, ", "))
+}
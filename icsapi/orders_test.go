@@ -0,0 +1,70 @@
+package icsapi_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/UK2Group/ics-cli/icsapi/icsapitest"
+)
+
+func TestValidateOrderSplitInventory(t *testing.T) {
+	// The stock of c1i.small in NYC1 is listed over two rows, neither enough for 3 servers
+	seed := icsapitest.DefaultSeed()
+	row := seed.Inventory[0]
+	row.Quantity, row.AutoProvisionQty = 2, 1
+	seed.Inventory[0] = row
+	seed.Inventory = append(seed.Inventory, row)
+
+	srv := httptest.NewServer(icsapitest.NewServer(seed, icsapitest.Options{}))
+	defer srv.Close()
+	client := icsapi.NewClient("test")
+	client.BaseURL = srv.URL
+
+	tests := []struct {
+		name     string
+		quantity int
+		wantErr  string
+	}{
+		{name: "covered by both rows", quantity: 3},
+		{name: "every server of both rows", quantity: 4},
+		{name: "more than both rows", quantity: 5, wantErr: "insufficient inventory for c1i.small in NYC1: 4 available, 5 requested"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := icsapi.OrderRequest{SKUProductName: "c1i.small", LocationCode: "NYC1", OperatingSystemProductCode: "DEBIAN_12", Quantity: tt.quantity}
+
+			catalog, err := client.GetOrderCatalog(context.Background(), order)
+			if err != nil {
+				t.Fatalf("GetOrderCatalog() error = %v", err)
+			}
+			if catalog.Item.Quantity != 4 || catalog.Item.AutoProvisionQty != 2 {
+				t.Errorf("catalog quantity %d, auto provision %d, want the sums 4 and 2", catalog.Item.Quantity, catalog.Item.AutoProvisionQty)
+			}
+			if catalog.Item.Price != row.Price || catalog.Item.CPUModel != row.CPUModel {
+				t.Errorf("catalog item %+v, want the price and hardware of the rows", *catalog.Item)
+			}
+
+			problems := catalog.Validate(order)
+			if tt.wantErr == "" && len(problems) > 0 {
+				t.Errorf("Validate() = %q, want no problems", problems)
+			}
+			if tt.wantErr != "" && (len(problems) != 1 || problems[0] != tt.wantErr) {
+				t.Errorf("Validate() = %q, want %q", problems, tt.wantErr)
+			}
+		})
+	}
+
+	// The fake API accepts the order too, taking the servers from both rows
+	order := icsapi.OrderRequest{SKUProductName: "c1i.small", LocationCode: "NYC1", OperatingSystemProductCode: "DEBIAN_12", Quantity: 3}
+	if _, err := client.PlaceOrder(context.Background(), order); err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	problems, err := client.ValidateOrder(context.Background(), icsapi.OrderRequest{SKUProductName: "c1i.small", LocationCode: "NYC1", OperatingSystemProductCode: "DEBIAN_12", Quantity: 2})
+	if err != nil || len(problems) != 1 || !strings.Contains(problems[0], "1 available") {
+		t.Errorf("ValidateOrder() after ordering 3 = %q, %v, want 1 available", problems, err)
+	}
+}
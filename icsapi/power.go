@@ -0,0 +1,110 @@
+package icsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PowerStatus reports whether a server is powered on
+func (c *Client) PowerStatus(ctx context.Context, serverID string) (bool, error) {
+	var response PowerStatusResponse
+
+	err := c.makeAPIRequest(ctx, "GET", 30*time.Second, fmt.Sprintf("/servers/%s/power/status", serverID), nil, &response)
+	if err != nil {
+		return false, fmt.Errorf("failed to get power status: %w", err)
+	}
+
+	return response.Data.IsPoweredOn, nil
+}
+
+// PowerOn sends a power on. It returns false if the API did not report success.
+func (c *Client) PowerOn(ctx context.Context, serverID string) (bool, error) {
+	return c.serverAction(ctx, fmt.Sprintf("/servers/%s/power/on", serverID))
+}
+
+// PowerOff sends a power off. It returns false if the API did not report success.
+func (c *Client) PowerOff(ctx context.Context, serverID string) (bool, error) {
+	return c.serverAction(ctx, fmt.Sprintf("/servers/%s/power/off", serverID))
+}
+
+// Reboot sends a reboot. It returns false if the API did not report success.
+func (c *Client) Reboot(ctx context.Context, serverID string) (bool, error) {
+	return c.serverAction(ctx, fmt.Sprintf("/servers/%s/power/reboot", serverID))
+}
+
+// Recovery boots a server into the recovery image. It returns false if the API did not report success.
+func (c *Client) Recovery(ctx context.Context, serverID string) (bool, error) {
+	return c.serverAction(ctx, fmt.Sprintf("/servers/%s/recovery/reboot", serverID))
+}
+
+// serverAction posts to a management endpoint answering with a GenericServerResponse
+func (c *Client) serverAction(ctx context.Context, path string) (bool, error) {
+	var response GenericServerResponse
+
+	err := c.makeAPIRequest(ctx, "POST", 60*time.Second, path, nil, &response)
+	if err != nil {
+		return false, err
+	}
+
+	return response.Data.Success, nil
+}
+
+// WaitForPowerState polls the power status of a server every interval until it is
+// powered on or off as requested. Bound the wait with a context deadline: an error
+// matching ErrWaitTimeout is returned when it expires. Transient errors while polling
+// are ignored, as the power status may be unavailable during a transition.
+func (c *Client) WaitForPowerState(ctx context.Context, serverID string, poweredOn bool, interval time.Duration) error {
+	state := "off"
+	if poweredOn {
+		state = "on"
+	}
+
+	for {
+		isPoweredOn, err := c.PowerStatus(ctx, serverID)
+		switch {
+		case err == nil && isPoweredOn == poweredOn:
+			return nil
+		case errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrNotFound):
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return waitError(ctx, fmt.Sprintf("power %s", state))
+		case <-time.After(interval):
+		}
+	}
+}
+
+// WaitForPowerCycle polls the power status of a server every interval until it has been
+// seen off and then on again, as after a reboot. A server that is on is not taken as
+// rebooted until a poll has seen it off, since a reboot over between two polls cannot be
+// told apart from one that never happened. Bound the wait with a context deadline: an
+// error matching ErrWaitTimeout is returned when it expires, saying whether the server
+// was ever seen off.
+func (c *Client) WaitForPowerCycle(ctx context.Context, serverID string, interval time.Duration) error {
+	seenOff := false
+
+	for {
+		isPoweredOn, err := c.PowerStatus(ctx, serverID)
+		switch {
+		case err == nil && !isPoweredOn:
+			seenOff = true
+		case err == nil && seenOff:
+			return nil
+		case errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrNotFound):
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			if !seenOff {
+				return waitError(ctx, "the server to power off, the reboot was never seen")
+			}
+			return waitError(ctx, "power on")
+		case <-time.After(interval):
+		}
+	}
+}
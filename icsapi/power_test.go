@@ -0,0 +1,104 @@
+package icsapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/UK2Group/ics-cli/icsapi/icsapitest"
+)
+
+func TestWaitForPowerCycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		powerDelay time.Duration // Time the fake API takes to power a server back on
+		action     func(*icsapi.Client, context.Context, string) (bool, error)
+		wantMin    time.Duration // The wait must not end before this
+		wantErr    string        // Part of the expected timeout error, if any
+	}{
+		{
+			// The reboot is over before the first poll, so it cannot be confirmed
+			name: "reboot never seen off", powerDelay: 0, action: (*icsapi.Client).Reboot,
+			wantErr: "never seen",
+		},
+		{name: "reboot seen off", powerDelay: 200 * time.Millisecond, action: (*icsapi.Client).Reboot, wantMin: 200 * time.Millisecond},
+		{name: "recovery seen off", powerDelay: 200 * time.Millisecond, action: (*icsapi.Client).Recovery, wantMin: 200 * time.Millisecond},
+		{name: "server stays off", powerDelay: 0, action: (*icsapi.Client).PowerOff, wantErr: "power on"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed := icsapitest.DefaultSeed()
+			srv := httptest.NewServer(icsapitest.NewServer(seed, icsapitest.Options{PowerDelay: tt.powerDelay}))
+			defer srv.Close()
+
+			client := icsapi.NewClient("test")
+			client.BaseURL = srv.URL
+			serverID := "1001" // web-01, powered on
+
+			start := time.Now()
+			if _, err := tt.action(client, context.Background(), serverID); err != nil {
+				t.Fatalf("power action failed: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			err := client.WaitForPowerCycle(ctx, serverID, 10*time.Millisecond)
+
+			if tt.wantErr != "" {
+				if !errors.Is(err, icsapi.ErrWaitTimeout) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want a timeout mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.wantMin {
+				t.Errorf("wait ended after %s, want at least %s", elapsed, tt.wantMin)
+			}
+
+			on, err := client.PowerStatus(context.Background(), serverID)
+			if err != nil || !on {
+				t.Errorf("server powered on = %t (err %v) after the wait, want on", on, err)
+			}
+		})
+	}
+}
+
+func TestWaitForPowerState(t *testing.T) {
+	tests := []struct {
+		name      string
+		action    func(*icsapi.Client, context.Context, string) (bool, error)
+		poweredOn bool
+		wantErr   error
+	}{
+		{name: "power off", action: (*icsapi.Client).PowerOff, poweredOn: false},
+		{name: "power on", action: (*icsapi.Client).PowerOn, poweredOn: true},
+		{name: "wrong state", action: (*icsapi.Client).PowerOff, poweredOn: true, wantErr: icsapi.ErrWaitTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(icsapitest.NewServer(nil, icsapitest.Options{}))
+			defer srv.Close()
+
+			client := icsapi.NewClient("test")
+			client.BaseURL = srv.URL
+
+			if _, err := tt.action(client, context.Background(), "1001"); err != nil {
+				t.Fatalf("power action failed: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			if err := client.WaitForPowerState(ctx, "1001", tt.poweredOn, 10*time.Millisecond); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package icsapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListOS retrieves the list of available operating systems for a server
+func (c *Client) ListOS(ctx context.Context, serverID string) ([]OS, error) {
+	var response OSListResponse
+
+	err := c.makeAPIRequest(ctx, "GET", 60*time.Second, fmt.Sprintf("/servers/%s/provision/os-list", serverID), nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OS list: %w", err)
+	}
+
+	return response.Data.OSList, nil
+}
+
+// ReinstallOS starts an operating system reinstall on a server.
+// It returns false if the API did not report success.
+func (c *Client) ReinstallOS(ctx context.Context, serverID, imageID, reason string) (bool, error) {
+	var response GenericServerResponse
+
+	requestData := map[string]string{
+		"os_image_id": imageID,
+		"reason":      reason,
+	}
+
+	err := c.makeAPIRequest(ctx, "POST", 60*time.Second, fmt.Sprintf("/servers/%s/provision/reload-os", serverID), requestData, &response)
+	if err != nil {
+		return false, err
+	}
+
+	return response.Data.Success, nil
+}
@@ -0,0 +1,101 @@
+package icsapi
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Quote is the estimated monthly cost of an order
+type Quote struct {
+	Currency  string      `json:"currency"`   // Currency of the prices, e.g. "USD"
+	Lines     []QuoteLine `json:"lines"`      // Monthly price of each item, per server
+	PerServer float64     `json:"per_server"` // Monthly price of one server
+	Quantity  int         `json:"quantity"`   // Number of servers ordered
+	Total     float64     `json:"total"`      // Monthly price of the whole order
+}
+
+// QuoteLine is one priced item of a quote
+type QuoteLine struct {
+	Item        string  `json:"item"`        // Kind of item, e.g. "server" or "operating_system"
+	Description string  `json:"description"` // Product name
+	Price       float64 `json:"price"`       // Monthly price per server
+}
+
+// Quote prices an order with the catalog. The order should first pass Validate:
+// an error is returned for a SKU or product code the catalog does not offer.
+// Additional bandwidth is not priced, as the API does not publish its price.
+func (cat *OrderCatalog) Quote(order OrderRequest) (*Quote, error) {
+	if cat.Item == nil {
+		return nil, fmt.Errorf("SKU %q is not offered in %s", order.SKUProductName, order.LocationCode)
+	}
+
+	serverPrice, err := strconv.ParseFloat(cat.Item.Price, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price %q for %s: %w", cat.Item.Price, cat.Item.SkuProductName, err)
+	}
+
+	quote := &Quote{
+		Currency: cat.Item.CurrencyCode,
+		Lines:    []QuoteLine{{Item: "server", Description: cat.Item.SkuProductName, Price: serverPrice}},
+		Quantity: order.Quantity,
+	}
+
+	// Operating systems may be licensed per physical core on top of their base price
+	osAddon := findProduct(cat.Addons.OperatingSystems.Products, func(p OSProduct) bool { return p.ProductCode == order.OperatingSystemProductCode })
+	if osAddon == nil {
+		return nil, fmt.Errorf("operating system %q is not available", order.OperatingSystemProductCode)
+	}
+	cores := cat.Item.CPUCores * max(cat.Item.CPUCount, 1)
+	quote.Lines = append(quote.Lines, QuoteLine{
+		Item:        "operating_system",
+		Description: osAddon.Name,
+		Price:       osAddon.Price + pricePerCore(osAddon.PricePerCore)*float64(cores),
+	})
+
+	if order.LicenseProductCode != "" {
+		license := findProduct(cat.Addons.Licenses.Products, func(p LicenseProduct) bool { return p.ProductCode == order.LicenseProductCode })
+		if license == nil {
+			return nil, fmt.Errorf("license %q is not available", order.LicenseProductCode)
+		}
+		quote.Lines = append(quote.Lines, QuoteLine{Item: "license", Description: license.Name, Price: license.Price})
+	}
+
+	if order.SupportLevelProductCode != "" {
+		support := findProduct(cat.Addons.SupportLevels.Products, func(p SupportProduct) bool { return p.ProductCode == order.SupportLevelProductCode })
+		if support == nil {
+			return nil, fmt.Errorf("support level %q is not available", order.SupportLevelProductCode)
+		}
+		quote.Lines = append(quote.Lines, QuoteLine{Item: "support", Description: support.Name, Price: support.Price})
+	}
+
+	for _, line := range quote.Lines {
+		quote.PerServer += line.Price
+	}
+	quote.Total = quote.PerServer * float64(quote.Quantity)
+
+	return quote, nil
+}
+
+// findProduct returns the first product matching, or nil
+func findProduct[T any](products []T, match func(T) bool) *T {
+	for i := range products {
+		if match(products[i]) {
+			return &products[i]
+		}
+	}
+	return nil
+}
+
+// pricePerCore reads the per-core price of an operating system, which the API returns
+// as a number, a numeric string or null
+func pricePerCore(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		price, _ := strconv.ParseFloat(v, 64)
+		return price
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,82 @@
+package icsapi
+
+import (
+	"math"
+	"testing"
+)
+
+func testCatalog() *OrderCatalog {
+	return &OrderCatalog{
+		Item: &InventoryDetails{SkuProductName: "c1i.small", CurrencyCode: "USD", Price: "100.00", CPUCores: 8, CPUCount: 2},
+		Addons: &AddonTypes{
+			OperatingSystems: OperatingSystemsSection{Products: []OSProduct{
+				{Name: "Debian 12", ProductCode: "DEBIAN_12"},
+				{Name: "Windows 2022 Standard", ProductCode: "WIN_2022_STD", Price: 10, PricePerCore: 1.5},
+				{Name: "Windows 2022 Datacenter", ProductCode: "WIN_2022_DC", Price: 20, PricePerCore: "2.25"},
+				{Name: "Custom", ProductCode: "CUSTOM", Price: 5, PricePerCore: nil},
+			}},
+			Licenses:      LicenseSection{Products: []LicenseProduct{{Name: "cPanel", ProductCode: "CPANEL", Price: 45}}},
+			SupportLevels: SupportSection{Products: []SupportProduct{{Name: "Premium", ProductCode: "PREMSUP", Price: 99}}},
+		},
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name      string
+		order     OrderRequest
+		perServer float64
+		total     float64
+		lines     int
+		wantErr   bool
+	}{
+		{name: "server and free OS", order: OrderRequest{OperatingSystemProductCode: "DEBIAN_12", Quantity: 1}, perServer: 100, total: 100, lines: 2},
+		{name: "quantity multiplies the total", order: OrderRequest{OperatingSystemProductCode: "DEBIAN_12", Quantity: 3}, perServer: 100, total: 300, lines: 2},
+		// 16 cores across both CPUs
+		{name: "per-core OS as a number", order: OrderRequest{OperatingSystemProductCode: "WIN_2022_STD", Quantity: 1}, perServer: 134, total: 134, lines: 2},
+		{name: "per-core OS as a string", order: OrderRequest{OperatingSystemProductCode: "WIN_2022_DC", Quantity: 2}, perServer: 156, total: 312, lines: 2},
+		{name: "per-core price null", order: OrderRequest{OperatingSystemProductCode: "CUSTOM", Quantity: 1}, perServer: 105, total: 105, lines: 2},
+		{name: "license and support", order: OrderRequest{OperatingSystemProductCode: "DEBIAN_12", LicenseProductCode: "CPANEL", SupportLevelProductCode: "PREMSUP", Quantity: 2}, perServer: 244, total: 488, lines: 4},
+		{name: "unknown OS", order: OrderRequest{OperatingSystemProductCode: "NOPE", Quantity: 1}, wantErr: true},
+		{name: "unknown license", order: OrderRequest{OperatingSystemProductCode: "DEBIAN_12", LicenseProductCode: "NOPE", Quantity: 1}, wantErr: true},
+		{name: "unknown support", order: OrderRequest{OperatingSystemProductCode: "DEBIAN_12", SupportLevelProductCode: "NOPE", Quantity: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := testCatalog().Quote(tt.order)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Quote() = %+v, want an error", quote)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Quote() error = %v", err)
+			}
+			if math.Abs(quote.PerServer-tt.perServer) > 1e-9 || math.Abs(quote.Total-tt.total) > 1e-9 {
+				t.Errorf("Quote() per server %v, total %v, want %v and %v", quote.PerServer, quote.Total, tt.perServer, tt.total)
+			}
+			if len(quote.Lines) != tt.lines {
+				t.Errorf("Quote() has %d lines, want %d", len(quote.Lines), tt.lines)
+			}
+			if quote.Currency != "USD" || quote.Quantity != tt.order.Quantity {
+				t.Errorf("Quote() currency %q, quantity %d", quote.Currency, quote.Quantity)
+			}
+		})
+	}
+}
+
+func TestQuoteCatalogErrors(t *testing.T) {
+	order := OrderRequest{SKUProductName: "c1i.small", OperatingSystemProductCode: "DEBIAN_12", Quantity: 1}
+
+	if _, err := (&OrderCatalog{}).Quote(order); err == nil {
+		t.Error("Quote() without an inventory item, want an error")
+	}
+
+	catalog := testCatalog()
+	catalog.Item.Price = "call us"
+	if _, err := catalog.Quote(order); err == nil {
+		t.Error("Quote() with an invalid server price, want an error")
+	}
+}
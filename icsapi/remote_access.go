@@ -0,0 +1,29 @@
+package icsapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IKVM generates an IPMI iKVM console link for a server
+func (c *Client) IKVM(ctx context.Context, serverID string) (string, error) {
+	return c.remoteAccess(ctx, fmt.Sprintf("/servers/%s/remote-access/ikvm", serverID))
+}
+
+// SOL generates a Serial Over LAN session link for a server
+func (c *Client) SOL(ctx context.Context, serverID string) (string, error) {
+	return c.remoteAccess(ctx, fmt.Sprintf("/servers/%s/remote-access/sol", serverID))
+}
+
+// remoteAccess posts to a remote access endpoint and returns the redirect link
+func (c *Client) remoteAccess(ctx context.Context, path string) (string, error) {
+	var response RemoteAccessResponse
+
+	err := c.makeAPIRequest(ctx, "POST", 60*time.Second, path, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.Data.Redirect, nil
+}
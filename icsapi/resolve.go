@@ -0,0 +1,158 @@
+package icsapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// AmbiguousServerError is returned by ResolveServer when a query matches more than one server
+type AmbiguousServerError struct {
+	Query      string   // Query that was resolved
+	Candidates []Server // Servers matching the query
+}
+
+// Error implements the error interface, listing the candidates
+func (e *AmbiguousServerError) Error() string {
+	var candidates []string
+	for _, server := range e.Candidates {
+		candidate := fmt.Sprintf("%d (%s", server.ServiceID, server.Hostname)
+		if server.FriendlyName != "" {
+			candidate += ", " + server.FriendlyName
+		}
+		if server.PublicIP != "" {
+			candidate += ", " + server.PublicIP
+		}
+		candidates = append(candidates, candidate+")")
+	}
+
+	return fmt.Sprintf("%q matches %d servers, use a service ID to select one: %s",
+		e.Query, len(e.Candidates), strings.Join(candidates, "; "))
+}
+
+// ResolveServer returns the server identified by a query. See ResolveServers for the
+// accepted queries. An *AmbiguousServerError is returned if several servers match.
+func (c *Client) ResolveServer(ctx context.Context, query string) (*Server, error) {
+	servers, err := c.ResolveServers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(servers) > 1 {
+		return nil, &AmbiguousServerError{Query: query, Candidates: servers}
+	}
+
+	return &servers[0], nil
+}
+
+// ResolveServers returns the servers matching a query, which can be a service ID,
+// a server ID, a hostname, a friendly name, a public or secondary IP address, or a
+// glob such as "web-*" matched against hostnames and friendly names.
+// Names are compared case-insensitively. An error matching ErrNotFound is returned
+// if no server matches.
+func (c *Client) ResolveServers(ctx context.Context, query string) ([]Server, error) {
+	servers, err := c.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ResolveServersIn(ctx, servers, query)
+}
+
+// ResolveServersIn is like ResolveServers, but matches against an already fetched
+// list of servers. Use it to resolve many queries with a single ListServers call.
+func (c *Client) ResolveServersIn(ctx context.Context, servers []Server, query string) ([]Server, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("no server specified")
+	}
+
+	matches, err := matchServers(servers, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Secondary IP addresses are only part of the server details, so they are
+	// looked up only when an IP address matches no primary IP
+	if len(matches) == 0 && net.ParseIP(query) != nil {
+		matches, err = c.matchSecondaryIP(ctx, servers, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, &notFoundError{fmt.Sprintf("no server matches %q", query)}
+	}
+
+	return matches, nil
+}
+
+// IsServerPattern reports whether a query is a glob that may match several servers
+func IsServerPattern(query string) bool {
+	return strings.ContainsAny(query, "*?[")
+}
+
+// matchServers returns the servers matching a query, using the fields of Server
+func matchServers(servers []Server, query string) ([]Server, error) {
+	var matches []Server
+
+	// Numbers are service IDs or server IDs
+	if id, err := strconv.Atoi(query); err == nil {
+		for _, server := range servers {
+			if server.ServiceID == id || server.ID == query {
+				matches = append(matches, server)
+			}
+		}
+		return matches, nil
+	}
+
+	if IsServerPattern(query) {
+		pattern := strings.ToLower(query)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid server pattern %q: %w", query, err)
+		}
+
+		for _, server := range servers {
+			hostname, _ := path.Match(pattern, strings.ToLower(server.Hostname))
+			friendlyName, _ := path.Match(pattern, strings.ToLower(server.FriendlyName))
+			if hostname || friendlyName {
+				matches = append(matches, server)
+			}
+		}
+		return matches, nil
+	}
+
+	for _, server := range servers {
+		if strings.EqualFold(server.Hostname, query) ||
+			strings.EqualFold(server.FriendlyName, query) ||
+			server.PublicIP == query {
+			matches = append(matches, server)
+		}
+	}
+	return matches, nil
+}
+
+// matchSecondaryIP returns the servers that have ip as a secondary IP address
+func (c *Client) matchSecondaryIP(ctx context.Context, servers []Server, ip string) ([]Server, error) {
+	var matches []Server
+
+	for _, server := range servers {
+		detail, err := c.GetServer(ctx, server.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, address := range detail.IPAddresses {
+			if address.IPAddress == ip {
+				matches = append(matches, server)
+				break
+			}
+		}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,145 @@
+package icsapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/UK2Group/ics-cli/icsapi"
+	"github.com/UK2Group/ics-cli/icsapi/icsapitest"
+)
+
+// newResolveClient returns a client for a fake API with servers whose names overlap
+func newResolveClient(t *testing.T) *icsapi.Client {
+	t.Helper()
+
+	server := func(id int, hostname, friendlyName, ip string, ips ...string) icsapitest.SeedServer {
+		seed := icsapitest.SeedServer{ServerType: "dedicated"}
+		seed.ServerID, seed.ServiceID = id, 500000+id
+		seed.Hostname, seed.FriendlyName, seed.PublicIP = hostname, friendlyName, ip
+		seed.IPAddresses = []icsapi.ServerIPAddress{{IPAddress: ip, IsPrimary: true}}
+		for _, secondary := range ips {
+			seed.IPAddresses = append(seed.IPAddresses, icsapi.ServerIPAddress{IPAddress: secondary})
+		}
+		return seed
+	}
+
+	seed := &icsapitest.Seed{
+		Username: "test",
+		Servers: []icsapitest.SeedServer{
+			server(1, "web-01.example.com", "web-01", "192.0.2.1", "198.51.100.1"),
+			server(2, "web-02.example.com", "web-02", "192.0.2.2"),
+			server(3, "db-01.example.com", "shared", "192.0.2.3"),
+			server(4, "db-02.example.com", "Shared", "192.0.2.4"),
+		},
+	}
+
+	srv := httptest.NewServer(icsapitest.NewServer(seed, icsapitest.Options{}))
+	t.Cleanup(srv.Close)
+
+	client := icsapi.NewClient("test")
+	client.BaseURL = srv.URL
+	return client
+}
+
+func TestResolveServers(t *testing.T) {
+	client := newResolveClient(t)
+
+	tests := []struct {
+		name         string
+		query        string
+		want         []int // Service IDs
+		wantNotFound bool
+		wantErr      string
+	}{
+		{name: "service ID", query: "500002", want: []int{500002}},
+		{name: "server ID", query: "3", want: []int{500003}},
+		{name: "hostname", query: "web-02.example.com", want: []int{500002}},
+		{name: "friendly name ignoring case", query: "WEB-01", want: []int{500001}},
+		{name: "duplicate friendly name", query: "shared", want: []int{500003, 500004}},
+		{name: "primary IP", query: "192.0.2.3", want: []int{500003}},
+		{name: "secondary IP", query: "198.51.100.1", want: []int{500001}},
+		{name: "glob on hostnames", query: "db-0?.example.com", want: []int{500003, 500004}},
+		{name: "glob on friendly names", query: "web-*", want: []int{500001, 500002}},
+		{name: "glob with a class", query: "web-0[2-9]", want: []int{500002}},
+		{name: "surrounding spaces", query: "  web-01 ", want: []int{500001}},
+		{name: "unknown name", query: "mail-01", wantNotFound: true},
+		{name: "unknown ID", query: "42", wantNotFound: true},
+		{name: "unknown IP", query: "203.0.113.9", wantNotFound: true},
+		{name: "glob matching nothing", query: "mail-*", wantNotFound: true},
+		{name: "invalid glob", query: "web-[", wantErr: "invalid server pattern"},
+		{name: "empty query", query: " ", wantErr: "no server specified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers, err := client.ResolveServers(context.Background(), tt.query)
+
+			switch {
+			case tt.wantNotFound:
+				if !errors.Is(err, icsapi.ErrNotFound) {
+					t.Fatalf("err = %v, want ErrNotFound", err)
+				}
+				return
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []int
+			for _, server := range servers {
+				got = append(got, server.ServiceID)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("service IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveServer(t *testing.T) {
+	client := newResolveClient(t)
+
+	tests := []struct {
+		name           string
+		query          string
+		want           int
+		wantCandidates int
+	}{
+		{name: "single match", query: "web-01", want: 500001},
+		{name: "ambiguous name", query: "shared", wantCandidates: 2},
+		{name: "ambiguous glob", query: "*.example.com", wantCandidates: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := client.ResolveServer(context.Background(), tt.query)
+
+			if tt.wantCandidates > 0 {
+				var ambiguous *icsapi.AmbiguousServerError
+				if !errors.As(err, &ambiguous) {
+					t.Fatalf("err = %v, want an AmbiguousServerError", err)
+				}
+				if len(ambiguous.Candidates) != tt.wantCandidates {
+					t.Errorf("%d candidates, want %d", len(ambiguous.Candidates), tt.wantCandidates)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if server.ServiceID != tt.want {
+				t.Errorf("service ID = %d, want %d", server.ServiceID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package icsapi
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how idempotent requests are retried.
+//
+// GET and PUT requests are retried on connection errors and on 429, 502, 503
+// and 504 responses. Requests that trigger an action, such as placing an order,
+// a power operation or setting a PXE URL (a PUT that reboots the server), are
+// never retried.
+type RetryPolicy struct {
+	MaxRetries int           // Maximum number of retries after the first attempt, 0 disables retries
+	BaseDelay  time.Duration // Delay before the first retry, doubled on each following retry
+	MaxDelay   time.Duration // Upper bound of the delay between two attempts
+}
+
+// DefaultRetryPolicy is the retry policy used by NewClient
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// backoff returns the jittered delay before the given retry (starting at 0).
+// The delay grows exponentially and is drawn between half and all of that value.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 0; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// transientError marks a failure that may succeed when retried
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+// It returns 0 if the header is absent or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,118 @@
+package icsapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{name: "absent", value: "", min: 0, max: 0},
+		{name: "seconds", value: "3", min: 3 * time.Second, max: 3 * time.Second},
+		{name: "zero", value: "0", min: 0, max: 0},
+		{name: "negative", value: "-5", min: 0, max: 0},
+		{name: "invalid", value: "soon", min: 0, max: 0},
+		{name: "date in the future", value: time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), min: 8 * time.Second, max: 10 * time.Second},
+		{name: "date in the past", value: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRetryAfter(tt.value)
+			if got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %s, want between %s and %s", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		retry int
+		min   time.Duration
+		max   time.Duration
+	}{
+		{retry: 0, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{retry: 1, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{retry: 3, min: 400 * time.Millisecond, max: 800 * time.Millisecond},
+		{retry: 10, min: 500 * time.Millisecond, max: time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if got := policy.backoff(tt.retry); got < tt.min || got > tt.max {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.retry, got, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestRequestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		responses    []int  // Status codes answered in turn, the last one repeated
+		retryAfter   string // Retry-After header sent with error responses
+		wantAttempts int32
+		wantErr      bool
+		wantStatus   int
+	}{
+		{name: "success", method: "GET", responses: []int{200}, wantAttempts: 1},
+		{name: "503 then success", method: "GET", responses: []int{503, 503, 200}, wantAttempts: 3},
+		{name: "502 and 504 then success", method: "PUT", responses: []int{502, 504, 200}, wantAttempts: 3},
+		{name: "503 until retries run out", method: "GET", responses: []int{503}, wantAttempts: 3, wantErr: true, wantStatus: 503},
+		{name: "429 with Retry-After", method: "GET", responses: []int{429, 200}, retryAfter: "1", wantAttempts: 2},
+		{name: "Retry-After beyond the maximum delay", method: "GET", responses: []int{429, 200}, retryAfter: "120", wantAttempts: 1, wantErr: true, wantStatus: 429},
+		{name: "POST is not retried", method: "POST", responses: []int{503, 200}, wantAttempts: 1, wantErr: true, wantStatus: 503},
+		{name: "404 is not retried", method: "GET", responses: []int{404, 200}, wantAttempts: 1, wantErr: true, wantStatus: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1)) - 1
+				status := tt.responses[min(n, len(tt.responses)-1)]
+				if status != http.StatusOK && tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+				w.Write([]byte(`{"success": true}`))
+			}))
+			defer srv.Close()
+
+			client := &Client{
+				BaseURL:    srv.URL,
+				APIKey:     "test",
+				HTTPClient: srv.Client(),
+				Retry:      RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second},
+			}
+
+			var result map[string]interface{}
+			err := client.makeAPIRequest(context.Background(), tt.method, 5*time.Second, "/test", nil, &result)
+
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %t", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantStatus != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus) {
+				t.Errorf("err = %v, want an APIError with status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package icsapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListServers returns all baremetal servers in the account
+func (c *Client) ListServers(ctx context.Context) ([]Server, error) {
+	var response ServerResponse
+
+	err := c.makeAPIRequest(ctx, "GET", 30*time.Second, "/servers", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server list: %w", err)
+	}
+
+	return response.Data, nil
+}
+
+// FindServerByServiceID returns the server with the given service ID
+func (c *Client) FindServerByServiceID(ctx context.Context, serviceID int) (*Server, error) {
+	servers, err := c.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, server := range servers {
+		if server.ServiceID == serviceID {
+			return &servers[i], nil
+		}
+	}
+
+	return nil, &notFoundError{fmt.Sprintf("server with Service ID %d not found", serviceID)}
+}
+
+// GetServer gets detailed information about a server
+func (c *Client) GetServer(ctx context.Context, serverID string) (*ServerDetail, error) {
+	var response ServerDetailResponse
+
+	err := c.makeAPIRequest(ctx, "GET", 30*time.Second, fmt.Sprintf("/servers/%s", serverID), nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server details: %w", err)
+	}
+
+	return &response.Data, nil
+}
+
+// SetFriendlyName sets the friendly name of a server
+func (c *Client) SetFriendlyName(ctx context.Context, serverID, name string) error {
+	var response RemoteAccessResponse
+
+	requestData := map[string]string{
+		"friendly_name": name,
+	}
+
+	return c.makeAPIRequest(ctx, "PUT", 60*time.Second, fmt.Sprintf("/servers/%s/friendly-name", serverID), requestData, &response)
+}
+
+// SetPXEURL sets a custom PXE boot URL on a server and requests a reboot.
+//
+// Unlike the other PUT requests it is not retried: the API reboots the server as soon
+// as it accepts the request, so a retry after a lost response would reboot it twice.
+// Callers that can tell whether the server picked up the URL, such as fleet apply,
+// may call it again themselves.
+func (c *Client) SetPXEURL(ctx context.Context, serverID, url string) error {
+	var response RemoteAccessResponse
+
+	requestData := map[string]string{
+		"pxe_script_url": url,
+	}
+
+	return c.request(ctx, "PUT", 60*time.Second, fmt.Sprintf("/servers/%s/set-pxe", serverID), requestData, &response, false)
+}
+
+// GetServerSSHKeys gets the SSH keys assigned to a server
+func (c *Client) GetServerSSHKeys(ctx context.Context, serverID string) ([]AssignedSSHKey, error) {
+	var response AssignedSSHKeysResponse
+
+	err := c.makeAPIRequest(ctx, "GET", 30*time.Second, fmt.Sprintf("/servers/%s/ssh-keys", serverID), nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SSH keys: %w", err)
+	}
+
+	return response.Data, nil
+}
@@ -0,0 +1,105 @@
+package icsapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListSSHKeys retrieves all SSH keys in the account
+func (c *Client) ListSSHKeys(ctx context.Context) ([]SSHKey, error) {
+	var response SSHKeysResponse
+
+	err := c.makeAPIRequest(ctx, "GET", 60*time.Second, "/ssh-keys", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SSH keys: %w", err)
+	}
+
+	return response.Data, nil
+}
+
+// FindSSHKeyByLabel returns the SSH key with the given label
+func (c *Client) FindSSHKeyByLabel(ctx context.Context, label string) (*SSHKey, error) {
+	keys, err := c.ListSSHKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		if key.Label == label {
+			return &keys[i], nil
+		}
+	}
+
+	return nil, &notFoundError{fmt.Sprintf("SSH Key with name %s not found", label)}
+}
+
+// AddSSHKey adds a new SSH key to the account and returns its ID
+func (c *Client) AddSSHKey(ctx context.Context, label, publicKey string) (int, error) {
+	var response SSHKeyAddResponse
+
+	requestData := map[string]string{
+		"label":      label,
+		"public_key": publicKey,
+	}
+
+	err := c.makeAPIRequest(ctx, "POST", 60*time.Second, "/ssh-keys", requestData, &response)
+	if err != nil {
+		return 0, err
+	}
+
+	return response.Data.ID, nil
+}
+
+// DeleteSSHKey deletes an SSH key from the account
+func (c *Client) DeleteSSHKey(ctx context.Context, keyID int) error {
+	var response GenericServerResponse
+
+	return c.makeAPIRequest(ctx, "DELETE", 60*time.Second, fmt.Sprintf("/ssh-keys/%d", keyID), nil, &response)
+}
+
+// RenameSSHKey updates the label of an SSH key
+func (c *Client) RenameSSHKey(ctx context.Context, keyID int, label string) error {
+	var response GenericServerResponse
+
+	requestData := map[string]string{
+		"label": label,
+	}
+
+	return c.makeAPIRequest(ctx, "PUT", 60*time.Second, fmt.Sprintf("/ssh-keys/%d", keyID), requestData, &response)
+}
+
+// AssignSSHKeys sets the SSH keys assigned to the server with the given service ID,
+// not its server ID. Keys take effect on the next reinstall.
+func (c *Client) AssignSSHKeys(ctx context.Context, serviceID int, keyIDs []int) (bool, error) {
+	var response SSHKeyAssignResponse
+
+	requestData := map[string][]int{
+		"ssh_key_ids": keyIDs,
+	}
+
+	err := c.makeAPIRequest(ctx, "PATCH", 60*time.Second, fmt.Sprintf("/servers/%d/ssh-keys/assign", serviceID), requestData, &response)
+	if err != nil {
+		return false, fmt.Errorf("failed to assign SSH keys: %w", err)
+	}
+
+	return response.Data, nil
+}
+
+// UnassignSSHKey removes an SSH key from the server with the given server ID. Unlike
+// AssignSSHKeys, the endpoint takes the server ID, not the service ID.
+// The key is removed on the next reinstall.
+func (c *Client) UnassignSSHKey(ctx context.Context, serverID string, keyID int) error {
+	var response SSHKeysResponse
+
+	requestData := map[string][]int{
+		"ssh_key_ids": {keyID},
+	}
+
+	err := c.makeAPIRequest(ctx, "PATCH", 60*time.Second, fmt.Sprintf("/servers/%s/ssh-keys/un-assign", serverID), requestData, &response)
+	if err != nil {
+		return fmt.Errorf("failed to unassign SSH key: %w", err)
+	}
+
+	return nil
+}
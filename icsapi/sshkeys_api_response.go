@@ -1,4 +1,4 @@
-package cmd
+package icsapi
 
 // SSHKeysResponse represents the response from the SSH keys API.
 // This is returned when listing all SSH keys for a customer.
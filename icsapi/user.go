@@ -0,0 +1,34 @@
+package icsapi
+
+import (
+	"context"
+	"time"
+)
+
+// UserResponse represents the structure of the user details API response.
+// This is returned when fetching the profile of the API key owner.
+type UserResponse struct {
+	StatusCode int    `json:"statusCode"` // HTTP status code returned by the API
+	Message    string `json:"message"`    // Human-readable message about the response
+	Data       struct {
+		UserProfile UserProfile `json:"userProfile"` // Profile of the authenticated user
+	} `json:"data"`
+}
+
+// UserProfile represents the profile of the user owning the API key.
+type UserProfile struct {
+	Username string `json:"username"` // Username of the account
+}
+
+// UserDetails returns the profile of the user owning the API key.
+// It is a cheap way to verify that the API key is valid.
+func (c *Client) UserDetails(ctx context.Context) (*UserProfile, error) {
+	var response UserResponse
+
+	err := c.makeAPIRequest(ctx, "GET", 10*time.Second, "/user/details", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.Data.UserProfile, nil
+}